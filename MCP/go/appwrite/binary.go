@@ -0,0 +1,175 @@
+package appwrite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"mime"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/files"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CallBinary performs r for an endpoint that returns file content rather than
+// JSON. Images are returned as image content and other files as an embedded
+// blob resource, as long as they fit within the configured size limit. When
+// called from a tool made by SaveTool the body is streamed to a file below
+// FILE_ROOT instead and only a summary is returned.
+func CallBinary(ctx context.Context, cfg *config.APIConfig, r *Request) (*mcp.CallToolResult, error) {
+	if r.Accept == "" {
+		r.Accept = "*/*"
+	}
+	c := Default()
+	resp, err := c.Send(ctx, cfg, r)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
+		}
+		return ErrorResult(&Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body, Request: r}), nil
+	}
+
+	if savePath, ok := ctx.Value(savePathKey{}).(string); ok {
+		return saveBody(resp, config.FromContext(ctx, cfg).FileRoot, savePath)
+	}
+
+	limit := c.maxBinaryBytes
+	if limit > 0 && resp.ContentLength > limit {
+		return tooLarge(resp.ContentLength, limit), nil
+	}
+	reader := io.Reader(resp.Body)
+	if limit > 0 {
+		reader = io.LimitReader(resp.Body, limit+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
+	}
+	if limit > 0 && int64(len(body)) > limit {
+		return tooLarge(-1, limit), nil
+	}
+
+	mimeType := contentType(resp, body)
+	data := base64.StdEncoding.EncodeToString(body)
+	summary := fmt.Sprintf("%s, %d bytes", mimeType, len(body))
+	if strings.HasPrefix(mimeType, "image/") {
+		return mcp.NewToolResultImage(summary, data, mimeType), nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(summary),
+			mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+				URI:      resp.Request.URL.Scheme + "://" + resp.Request.URL.Host + resp.Request.URL.Path,
+				MIMEType: mimeType,
+				Blob:     data,
+			}),
+		},
+	}, nil
+}
+
+func tooLarge(size, limit int64) *mcp.CallToolResult {
+	if size < 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("Response exceeds the %d byte limit for inline content (MAX_BINARY_BYTES); use the save_ tool of this endpoint to store it below FILE_ROOT instead", limit))
+	}
+	return mcp.NewToolResultError(fmt.Sprintf("Response is %d bytes, above the %d byte limit for inline content (MAX_BINARY_BYTES); use the save_ tool of this endpoint to store it below FILE_ROOT instead", size, limit))
+}
+
+// contentType prefers the upstream Content-Type header and falls back to
+// sniffing the body.
+func contentType(resp *http.Response, body []byte) string {
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mediaType != "" {
+		return mediaType
+	}
+	if len(body) == 0 {
+		return "application/octet-stream"
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(body))
+	return mediaType
+}
+
+// saveBody streams the response to a new file at savePath below root. It
+// never overwrites an existing file.
+func saveBody(resp *http.Response, root, savePath string) (*mcp.CallToolResult, error) {
+	target, err := files.Resolve(root, savePath)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Invalid savePath", err), nil
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+	if errors.Is(err, fs.ErrExist) {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid savePath: %s already exists; choose a new path", savePath)), nil
+	}
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to create file", err), nil
+	}
+	size, err := io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+		return mcp.NewToolResultErrorFromErr("Failed to save response", err), nil
+	}
+
+	summary, err := json.MarshalIndent(map[string]any{
+		"path":     target,
+		"size":     size,
+		"mimeType": contentType(resp, nil),
+	}, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+	}
+	return mcp.NewToolResultText(string(summary)), nil
+}
+
+type savePathKey struct{}
+
+// SaveTool derives from a tool that returns file content a tool that saves
+// the content to a new file below FILE_ROOT instead, named like the tool
+// with a save_ prefix in place of get_. Writing files is not read-only, so
+// the derived tool is left out of READ_ONLY mode and replay, while the tool
+// it is made from stays read-only and never touches the disk.
+func SaveTool(tool models.Tool) models.Tool {
+	def := tool.Definition
+	def.Name = "save_" + strings.TrimPrefix(def.Name, "get_")
+	def.Description = def.Description + ", saved to a file below FILE_ROOT"
+	def.Annotations.Title = def.Annotations.Title + " (Save)"
+	def.Annotations.ReadOnlyHint = mcp.ToBoolPtr(false)
+	def.Annotations.DestructiveHint = mcp.ToBoolPtr(false)
+	def.Annotations.IdempotentHint = mcp.ToBoolPtr(false)
+	def.InputSchema.Properties = maps.Clone(def.InputSchema.Properties)
+	if def.InputSchema.Properties == nil {
+		def.InputSchema.Properties = map[string]any{}
+	}
+	def.InputSchema.Properties["savePath"] = map[string]any{
+		"type":        "string",
+		"description": "Path of the new file below FILE_ROOT; existing files are not overwritten.",
+	}
+	def.InputSchema.Required = append(slices.Clone(def.InputSchema.Required), "savePath")
+
+	handler := tool.Handler
+	tool.Definition = def
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		savePath, err := request.RequireString("savePath")
+		if err != nil || savePath == "" {
+			return mcp.NewToolResultError("Missing required parameter: savePath"), nil
+		}
+		return handler(context.WithValue(ctx, savePathKey{}, savePath), request)
+	}
+	return tool
+}
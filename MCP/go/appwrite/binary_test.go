@@ -0,0 +1,187 @@
+package appwrite
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var png = []byte("\x89PNG\r\n\x1a\n0000")
+
+// fileServer serves /image.png, /doc.pdf, /big with a Content-Length and
+// /stream without one.
+func fileServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image.png":
+			w.Write(png)
+		case "/doc.pdf":
+			w.Header().Set("Content-Type", "application/pdf; charset=binary")
+			w.Write([]byte("%PDF-1.4"))
+		case "/big":
+			w.Header().Set("Content-Length", "20")
+			w.Write(bytes.Repeat([]byte("x"), 20))
+		case "/stream":
+			for i := 0; i < 4; i++ {
+				w.Write(bytes.Repeat([]byte("x"), 5))
+				w.(http.Flusher).Flush()
+			}
+		default:
+			w.Write([]byte("downloaded"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCallBinary(t *testing.T) {
+	srv := fileServer(t)
+	SetDefault(NewClient(&config.ClientConfig{}))
+	cfg := &config.APIConfig{BaseURL: srv.URL}
+
+	result, err := CallBinary(context.Background(), cfg, &Request{Method: http.MethodGet, Path: "/image.png"})
+	if err != nil || result.IsError || len(result.Content) != 2 {
+		t.Fatalf("image: %v, %+v", err, result)
+	}
+	image, ok := result.Content[1].(mcp.ImageContent)
+	if !ok || image.MIMEType != "image/png" || image.Data != base64.StdEncoding.EncodeToString(png) {
+		t.Errorf("image content = %+v", result.Content[1])
+	}
+
+	result, err = CallBinary(context.Background(), cfg, &Request{Method: http.MethodGet, Path: "/doc.pdf"})
+	if err != nil || result.IsError || len(result.Content) != 2 {
+		t.Fatalf("pdf: %v, %+v", err, result)
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "application/pdf, 8 bytes" {
+		t.Errorf("summary = %+v", result.Content[0])
+	}
+	resource, ok := result.Content[1].(mcp.EmbeddedResource)
+	if !ok {
+		t.Fatalf("content = %T, want an embedded resource", result.Content[1])
+	}
+	blob, ok := resource.Resource.(mcp.BlobResourceContents)
+	if !ok || blob.MIMEType != "application/pdf" || blob.URI != srv.URL+"/doc.pdf" || blob.Blob != base64.StdEncoding.EncodeToString([]byte("%PDF-1.4")) {
+		t.Errorf("blob = %+v", resource.Resource)
+	}
+}
+
+func TestMaxBinaryBytes(t *testing.T) {
+	srv := fileServer(t)
+	SetDefault(NewClient(&config.ClientConfig{MaxBinaryBytes: 10}))
+	cfg := &config.APIConfig{BaseURL: srv.URL}
+
+	for path, want := range map[string]string{
+		"/big":    "Response is 20 bytes, above the 10 byte limit",
+		"/stream": "Response exceeds the 10 byte limit",
+	} {
+		result, err := CallBinary(context.Background(), cfg, &Request{Method: http.MethodGet, Path: path})
+		if err != nil || !result.IsError {
+			t.Fatalf("%s: %v, %+v", path, err, result)
+		}
+		if text := models.ResultText(result); !strings.Contains(text, want) || !strings.Contains(text, "MAX_BINARY_BYTES") {
+			t.Errorf("%s: %q", path, text)
+		}
+	}
+	if result, err := CallBinary(context.Background(), cfg, &Request{Method: http.MethodGet, Path: "/file"}); err != nil || result.IsError {
+		t.Errorf("response within the limit: %v, %+v", err, result)
+	}
+}
+
+// downloadTool returns a read-only tool serving path and the tool SaveTool
+// derives from it.
+func downloadTool(cfg *config.APIConfig, path string) (models.Tool, models.Tool) {
+	tool := models.Tool{
+		Definition: mcp.NewTool("get_download",
+			mcp.WithDescription("Download"),
+			mcp.WithTitleAnnotation("Download"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("fileId", mcp.Required()),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return CallBinary(ctx, cfg, &Request{Method: http.MethodGet, Path: path})
+		},
+	}
+	return tool, SaveTool(tool)
+}
+
+func call(t *testing.T, tool models.Tool, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	var request mcp.CallToolRequest
+	request.Params.Arguments = args
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestSaveTool(t *testing.T) {
+	srv := fileServer(t)
+	SetDefault(NewClient(&config.ClientConfig{MaxBinaryBytes: 10}))
+	root := t.TempDir()
+	tool, save := downloadTool(&config.APIConfig{BaseURL: srv.URL, FileRoot: root}, "/big")
+
+	def := save.Definition
+	if def.Name != "save_download" || *def.Annotations.ReadOnlyHint || *def.Annotations.DestructiveHint {
+		t.Errorf("save tool = %s, annotations %+v", def.Name, def.Annotations)
+	}
+	if _, ok := def.InputSchema.Properties["fileId"]; !ok || len(def.InputSchema.Required) != 2 || def.InputSchema.Required[1] != "savePath" {
+		t.Errorf("save tool schema = %+v", def.InputSchema)
+	}
+	if _, ok := tool.Definition.InputSchema.Properties["savePath"]; ok || !*tool.Definition.Annotations.ReadOnlyHint {
+		t.Errorf("SaveTool changed the read-only tool: %+v", tool.Definition)
+	}
+
+	// The read-only tool never writes, even when asked to
+	if result := call(t, tool, map[string]any{"fileId": "1", "savePath": "out.bin"}); !result.IsError {
+		t.Errorf("read-only tool: %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(root, "out.bin")); !os.IsNotExist(err) {
+		t.Errorf("read-only tool wrote a file: %v", err)
+	}
+
+	// Saving bypasses the inline limit
+	if result := call(t, save, map[string]any{"fileId": "1", "savePath": "out.bin"}); result.IsError {
+		t.Fatalf("save: %s", models.ResultText(result))
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "out.bin")); len(data) != 20 {
+		t.Errorf("saved %d bytes, want 20", len(data))
+	}
+	if result := call(t, save, map[string]any{"fileId": "1"}); !result.IsError {
+		t.Errorf("save without savePath: %+v", result)
+	}
+}
+
+func TestSaveDoesNotOverwrite(t *testing.T) {
+	srv := fileServer(t)
+	SetDefault(NewClient(&config.ClientConfig{}))
+	root := t.TempDir()
+	_, save := downloadTool(&config.APIConfig{BaseURL: srv.URL, FileRoot: root}, "/file")
+	existing := filepath.Join(root, "keep.txt")
+	os.WriteFile(existing, []byte("original"), 0o600)
+
+	result := call(t, save, map[string]any{"savePath": "keep.txt"})
+	if !result.IsError || !strings.Contains(models.ResultText(result), "already exists") {
+		t.Fatalf("save over existing file: %+v", result)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "original" {
+		t.Errorf("existing file overwritten with %q", data)
+	}
+
+	if result := call(t, save, map[string]any{"savePath": "new.txt"}); result.IsError {
+		t.Fatalf("save to new file: %s", models.ResultText(result))
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "new.txt")); string(data) != "downloaded" {
+		t.Errorf("new file holds %q", data)
+	}
+}
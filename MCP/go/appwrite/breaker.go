@@ -0,0 +1,110 @@
+package appwrite
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// breaker is a circuit breaker for one Appwrite base URL. After threshold
+// consecutive failures it opens and rejects requests until cooldown has
+// passed, then lets a single probe through to decide whether to close again.
+type breaker struct {
+	baseURL   string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// ErrCircuitOpen is returned while requests to an unavailable Appwrite
+// instance are short-circuited.
+type ErrCircuitOpen struct {
+	BaseURL    string
+	Failures   int
+	RetryAfter time.Duration
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("Appwrite at %s looks unavailable after %d consecutive failures; not sending requests for another %s", e.BaseURL, e.Failures, e.RetryAfter.Round(100*time.Millisecond))
+}
+
+// allow returns an error if the circuit is open. Once the cooldown has passed
+// exactly one caller is let through as a probe.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	remaining := b.cooldown - time.Since(b.openedAt)
+	if remaining > 0 || b.probing {
+		return &ErrCircuitOpen{BaseURL: b.baseURL, Failures: b.failures, RetryAfter: max(remaining, 0)}
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of an attempt.
+func (b *breaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// abandon ends an attempt allow let through without an outcome: one that was
+// never sent, was cancelled or was refused by the egress policy. A probe that
+// could not run neither keeps the circuit open for good nor closes it.
+func (b *breaker) abandon() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// breakers hands out one breaker per base URL.
+type breakers struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu     sync.Mutex
+	byBase map[string]*breaker
+}
+
+func (bs *breakers) get(baseURL string) *breaker {
+	if bs.threshold <= 0 {
+		return nil
+	}
+	key := strings.TrimRight(strings.ToLower(baseURL), "/")
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if bs.byBase == nil {
+		bs.byBase = map[string]*breaker{}
+	}
+	b, ok := bs.byBase[key]
+	if !ok {
+		b = &breaker{baseURL: baseURL, threshold: bs.threshold, cooldown: bs.cooldown}
+		bs.byBase[key] = b
+	}
+	return b
+}
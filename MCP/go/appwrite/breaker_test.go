@@ -0,0 +1,105 @@
+package appwrite
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/config"
+)
+
+func TestBreaker(t *testing.T) {
+	srv, count := flaky(t, nil, http.StatusBadGateway, http.StatusBadGateway)
+	c := NewClient(&config.ClientConfig{BreakerThreshold: 2, BreakerCooldown: 50 * time.Millisecond})
+	cfg := &config.APIConfig{BaseURL: srv.URL}
+	req := &Request{Method: http.MethodGet, Path: "/"}
+
+	for i := 0; i < 2; i++ {
+		if resp, err := c.Do(context.Background(), cfg, req); err != nil || resp.StatusCode != http.StatusBadGateway {
+			t.Fatalf("failure %d: resp %v, err %v", i, resp, err)
+		}
+	}
+	// Open: requests fail fast without reaching Appwrite
+	var open *ErrCircuitOpen
+	if _, err := c.Do(context.Background(), cfg, req); !errors.As(err, &open) || open.RetryAfter <= 0 {
+		t.Fatalf("open circuit: err = %v", err)
+	}
+	if n := count.Load(); n != 2 {
+		t.Fatalf("%d requests reached Appwrite, want 2", n)
+	}
+
+	// After the cooldown exactly one probe is let through
+	time.Sleep(60 * time.Millisecond)
+	b := c.breakers.get(cfg.BaseURL)
+	if err := b.allow(); err != nil {
+		t.Fatalf("probe refused: %v", err)
+	}
+	if err := b.allow(); !errors.As(err, &open) {
+		t.Fatalf("second probe: err = %v", err)
+	}
+	b.abandon()
+
+	// A successful probe closes the circuit
+	if resp, err := c.Do(context.Background(), cfg, req); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("probe: resp %v, err %v", resp, err)
+	}
+	if resp, err := c.Do(context.Background(), cfg, req); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("after closing: resp %v, err %v", resp, err)
+	}
+}
+
+func TestBreakerReopensOnFailedProbe(t *testing.T) {
+	b := &breaker{threshold: 1, cooldown: 20 * time.Millisecond}
+	b.record(true)
+	if b.allow() == nil {
+		t.Fatal("circuit not open after threshold")
+	}
+	time.Sleep(25 * time.Millisecond)
+	if err := b.allow(); err != nil {
+		t.Fatalf("probe refused: %v", err)
+	}
+	b.record(true)
+	if b.allow() == nil {
+		t.Error("circuit not open again after failed probe")
+	}
+}
+
+func TestCancelledProbeKeepsBreakerHalfOpen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	c := NewClient(&config.ClientConfig{BreakerThreshold: 2, BreakerCooldown: 20 * time.Millisecond})
+	cfg := &config.APIConfig{BaseURL: srv.URL}
+
+	for i := 0; i < 2; i++ {
+		if resp, err := c.Do(context.Background(), cfg, &Request{Method: http.MethodPost, Path: "/"}); err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("failure %d: resp %v, err %v", i, resp, err)
+		}
+	}
+	time.Sleep(25 * time.Millisecond)
+
+	// The probe times out before Appwrite answers
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := c.Do(ctx, cfg, &Request{Method: http.MethodGet, Path: "/"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("probe: err = %v, want deadline exceeded", err)
+	}
+
+	// The circuit is still half-open: one new probe, no closed circuit
+	b := c.breakers.get(cfg.BaseURL)
+	if err := b.allow(); err != nil {
+		t.Fatalf("next probe refused: %v", err)
+	}
+	var open *ErrCircuitOpen
+	if err := b.allow(); !errors.As(err, &open) || open.Failures != 2 {
+		t.Errorf("circuit closed by a cancelled probe: err = %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+package appwrite
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/appwrite/mcp-server/audit"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/egress"
+	"github.com/appwrite/mcp-server/metrics"
+	"github.com/appwrite/mcp-server/tracing"
+)
+
+// Client performs requests against the Appwrite REST API on behalf of tools.
+type Client struct {
+	httpClient     *http.Client
+	guardedClient  *http.Client // For base URLs supplied by clients
+	tlsClients     sync.Map     // tlsKey to *http.Client, for configs with their own TLS settings
+	maxBinaryBytes int64
+	retry          retryPolicy
+	breakers       *breakers
+	slots          *slots // Caps requests in flight, nil for no limit
+}
+
+type tlsKey struct {
+	tls       *tls.Config
+	untrusted bool
+}
+
+// Request describes a single upstream call. Path is relative to the configured
+// base URL and must already be escaped.
+type Request struct {
+	Method      string
+	Path        string
+	Query       url.Values
+	JSON        any       // Encoded as the JSON request body when set
+	Body        io.Reader // Raw request body, used when JSON is nil
+	ContentType string    // Content type of Body
+	Security    []string  // Security schemes from the operation's security block
+	Accept      string    // Accepted response types, application/json when empty
+	Scope       string    // API key scope the operation requires, used in error hints
+}
+
+// Response is a fully read upstream response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Request    *Request
+}
+
+func NewClient(cfg *config.ClientConfig) *Client {
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConns,
+	}
+	// Client-supplied base URLs are dialed directly, bypassing any proxy, so
+	// the policy sees the address actually connected to
+	policy := cfg.Egress
+	if policy == nil {
+		policy = &egress.Policy{}
+	}
+	guarded := transport.Clone()
+	guarded.Proxy = nil
+	guarded.DialContext = policy.Dialer(dialer).DialContext
+	return &Client{
+		httpClient:     &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		guardedClient:  &http.Client{Transport: guarded, Timeout: cfg.Timeout, CheckRedirect: policy.CheckRedirect},
+		maxBinaryBytes: cfg.MaxBinaryBytes,
+		retry:          retryPolicy{max: cfg.RetryMax, baseDelay: cfg.RetryBaseDelay, maxDelay: cfg.RetryMaxDelay},
+		breakers:       &breakers{threshold: cfg.BreakerThreshold, cooldown: cfg.BreakerCooldown},
+		slots:          newSlots(cfg.MaxConcurrent, cfg.QueueTimeout),
+	}
+}
+
+var defaultClient atomic.Pointer[Client]
+
+// SetDefault replaces the client used by Call and the other package level
+// helpers.
+func SetDefault(c *Client) {
+	defaultClient.Store(c)
+}
+
+// Default returns the shared client, creating one with default settings if
+// SetDefault was never called.
+func Default() *Client {
+	if c := defaultClient.Load(); c != nil {
+		return c
+	}
+	cfg, err := config.LoadClientConfig()
+	if err != nil {
+		cfg = &config.ClientConfig{}
+	}
+	defaultClient.CompareAndSwap(nil, NewClient(cfg))
+	return defaultClient.Load()
+}
+
+// Do sends r to the Appwrite instance described by cfg and reads the whole
+// response. The request is bound to ctx, so cancelling the tool call aborts the
+// upstream request.
+func (c *Client) Do(ctx context.Context, cfg *config.APIConfig, r *Request) (*Response, error) {
+	resp, err := c.Send(ctx, cfg, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody, Request: r}, nil
+}
+
+// Send is like Do but leaves reading the body to the caller, who must close
+// it. It is used for binary responses that may be too large to buffer.
+//
+// A config carried by ctx, such as the one of the current HTTP session,
+// takes precedence over cfg.
+//
+// Requests for a config marked Untrusted are checked against the egress
+// policy on every connection and redirect.
+//
+// Idempotent requests are retried with backoff on network errors, 429 and 5xx
+// responses, and every attempt goes through the circuit breaker for the base
+// URL.
+//
+// With a concurrency limit, each attempt waits for a free slot and holds it
+// until its response body is closed.
+func (c *Client) Send(ctx context.Context, cfg *config.APIConfig, r *Request) (*http.Response, error) {
+	cfg = config.FromContext(ctx, cfg)
+	var encoded []byte
+	if r.JSON != nil {
+		var err error
+		encoded, err = json.Marshal(r.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+	// Streamed bodies can only be sent once
+	canRetry := idempotent(r.Method) && (r.Body == nil || r.JSON != nil)
+	circuit := c.breakers.get(cfg.BaseURL)
+	httpClient := c.clientFor(cfg)
+
+	req, err := c.newRequest(ctx, cfg, r, encoded)
+	if err != nil {
+		return nil, err
+	}
+	if err := circuit.allow(); err != nil {
+		return nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			// Same inputs as the first attempt, so this cannot fail
+			req, _ = c.newRequest(ctx, cfg, r, encoded)
+		}
+		release, err := c.slots.acquire(ctx)
+		if err != nil {
+			circuit.abandon()
+			return nil, err
+		}
+		done := metrics.Upstream(ctx, r.Method)
+		traced := tracing.Upstream(req)
+		resp, err := httpClient.Do(req)
+		traced(resp, err)
+		if resp != nil {
+			resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+		} else {
+			release()
+		}
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		done(status)
+		audit.Upstream(ctx, r.Method, r.Path, status)
+		retryable := transient(ctx, resp, err)
+		if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled) || egress.IsBlocked(err)) {
+			// Appwrite was never heard from, so the attempt says nothing
+			// about its health
+			circuit.abandon()
+		} else {
+			// Rate limiting means Appwrite is up, so it does not count against the circuit
+			circuit.record(retryable && (resp == nil || resp.StatusCode != http.StatusTooManyRequests))
+		}
+
+		if !retryable || !canRetry || attempt >= c.retry.max {
+			return resp, err
+		}
+		wait, ok := c.retry.delay(attempt, resp)
+		if !ok || circuit.allow() != nil {
+			// Hand back the last outcome rather than a circuit error
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, wait); err != nil {
+			circuit.abandon()
+			return nil, err
+		}
+	}
+}
+
+// clientFor returns the HTTP client for requests made with cfg: the guarded
+// one for client-supplied base URLs, with the TLS settings of cfg if it has
+// any.
+func (c *Client) clientFor(cfg *config.APIConfig) *http.Client {
+	base := c.httpClient
+	if cfg.Untrusted {
+		base = c.guardedClient
+	}
+	if cfg.TLS == nil {
+		return base
+	}
+	key := tlsKey{tls: cfg.TLS, untrusted: cfg.Untrusted}
+	if hc, ok := c.tlsClients.Load(key); ok {
+		return hc.(*http.Client)
+	}
+	transport := base.Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg.TLS
+	hc := *base
+	hc.Transport = transport
+	actual, _ := c.tlsClients.LoadOrStore(key, &hc)
+	return actual.(*http.Client)
+}
+
+func (c *Client) newRequest(ctx context.Context, cfg *config.APIConfig, r *Request, encoded []byte) (*http.Request, error) {
+	body := r.Body
+	contentType := r.ContentType
+	if encoded != nil {
+		body = bytes.NewReader(encoded)
+		contentType = "application/json"
+	}
+
+	target := strings.TrimRight(cfg.BaseURL, "/") + r.Path
+	if len(r.Query) > 0 {
+		target += "?" + r.Query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if err := applySecurity(req.Header, cfg, r.Security); err != nil {
+		return nil, err
+	}
+	accept := r.Accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Set("Accept", accept)
+	return req, nil
+}
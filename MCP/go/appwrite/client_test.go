@@ -0,0 +1,134 @@
+package appwrite
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/egress"
+)
+
+func TestUntrustedConfigFollowsEgressPolicy(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"secret":true}`))
+	}))
+	defer internal.Close()
+	entry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer entry.Close()
+
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+	c := NewClient(&config.ClientConfig{
+		RetryMax: 3,
+		Egress:   &egress.Policy{Allow: []string{entry.URL}, AllowNetwork: []*net.IPNet{loopback}},
+	})
+	req := &Request{Method: http.MethodGet, Path: "/health"}
+
+	// Operator-configured base URLs are not restricted
+	resp, err := c.Do(context.Background(), &config.APIConfig{BaseURL: entry.URL}, req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("trusted request: %v", err)
+	}
+	// Client-supplied ones may not be redirected elsewhere, and are not retried
+	_, err = c.Do(context.Background(), &config.APIConfig{BaseURL: entry.URL, Untrusted: true}, req)
+	if !egress.IsBlocked(err) {
+		t.Fatalf("untrusted request: err = %v, want blocked", err)
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	unblock := make(chan struct{})
+	started := make(chan struct{}, 2)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	c := NewClient(&config.ClientConfig{MaxConcurrent: 1, QueueTimeout: 50 * time.Millisecond})
+	cfg := &config.APIConfig{BaseURL: upstream.URL}
+	req := &Request{Method: http.MethodGet, Path: "/health"}
+
+	first := make(chan error)
+	go func() {
+		_, err := c.Do(context.Background(), cfg, req)
+		first <- err
+	}()
+	<-started
+	_, err := c.Do(context.Background(), cfg, req)
+	var busy *ErrBusy
+	if !errors.As(err, &busy) || busy.Limit != 1 || busy.RetryAfter <= 0 {
+		t.Fatalf("second request: err = %v, want ErrBusy", err)
+	}
+
+	close(unblock)
+	if err := <-first; err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	// The slot is released once the response is read
+	if _, err := c.Do(context.Background(), cfg, req); err != nil {
+		t.Fatalf("third request: %v", err)
+	}
+}
+
+func TestBusyProbeDoesNotWedgeBreaker(t *testing.T) {
+	failing := true
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer flaky.Close()
+	unblock := make(chan struct{})
+	started := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-unblock
+		w.Write([]byte(`{}`))
+	}))
+	defer slow.Close()
+
+	c := NewClient(&config.ClientConfig{
+		BreakerThreshold: 1,
+		BreakerCooldown:  10 * time.Millisecond,
+		MaxConcurrent:    1,
+		QueueTimeout:     20 * time.Millisecond,
+	})
+	req := &Request{Method: http.MethodGet, Path: "/health"}
+	flakyCfg := &config.APIConfig{BaseURL: flaky.URL}
+
+	// Open the circuit
+	if resp, _ := c.Do(context.Background(), flakyCfg, req); resp == nil || resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("first request: %+v", resp)
+	}
+	// Take the only slot, then let the probe find none
+	slowDone := make(chan struct{})
+	go func() {
+		c.Do(context.Background(), &config.APIConfig{BaseURL: slow.URL}, req)
+		close(slowDone)
+	}()
+	<-started
+	time.Sleep(20 * time.Millisecond)
+	var busy *ErrBusy
+	if _, err := c.Do(context.Background(), flakyCfg, req); !errors.As(err, &busy) {
+		t.Fatalf("probe: err = %v, want ErrBusy", err)
+	}
+	close(unblock)
+	<-slowDone
+
+	// The next request is let through as a probe and closes the circuit
+	failing = false
+	resp, err := c.Do(context.Background(), flakyCfg, req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("request after busy probe: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package appwrite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// busyRetryAfter is the wait suggested to callers turned away because every
+// upstream slot stayed taken.
+const busyRetryAfter = time.Second
+
+// ErrBusy is returned when a request found no free upstream slot within the
+// queue timeout.
+type ErrBusy struct {
+	Limit      int
+	Waited     time.Duration
+	RetryAfter time.Duration
+}
+
+func (e *ErrBusy) Error() string {
+	return fmt.Sprintf("the server is at its limit of %d concurrent Appwrite requests and none finished within %s; retry after %s", e.Limit, e.Waited, e.RetryAfter)
+}
+
+// slots caps the number of upstream requests in flight across all clients.
+// A nil *slots imposes no limit.
+type slots struct {
+	sem     chan struct{}
+	timeout time.Duration
+}
+
+func newSlots(limit int, timeout time.Duration) *slots {
+	if limit <= 0 {
+		return nil
+	}
+	return &slots{sem: make(chan struct{}, limit), timeout: timeout}
+}
+
+// acquire waits up to the queue timeout for a free slot. The returned
+// function gives it back and may be called more than once.
+func (s *slots) acquire(ctx context.Context) (release func(), err error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.sem <- struct{}{}:
+	default:
+		timer := time.NewTimer(s.timeout)
+		defer timer.Stop()
+		select {
+		case s.sem <- struct{}{}:
+		case <-timer.C:
+			return nil, &ErrBusy{Limit: cap(s.sem), Waited: s.timeout, RetryAfter: busyRetryAfter}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-s.sem }) }, nil
+}
+
+// releaseBody gives back the slot of a request once its response body is
+// closed, so streamed responses hold their slot while they are read.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,128 @@
+package appwrite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Error is the structured content returned for failed upstream calls.
+type Error struct {
+	Status     int    `json:"status"`
+	Code       string `json:"code,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Message    string `json:"message"`
+	Version    string `json:"version,omitempty"`
+	Retryable  bool   `json:"retryable"`
+	RetryAfter string `json:"retryAfter,omitempty"`
+	Hint       string `json:"hint,omitempty"`
+}
+
+// ParseError decodes an Appwrite error response. Bodies that are not an
+// Appwrite error object are kept verbatim as the message.
+func ParseError(resp *Response) *Error {
+	e := &Error{
+		Status:     resp.StatusCode,
+		Retryable:  retryableStatus(resp.StatusCode),
+		RetryAfter: resp.Header.Get("Retry-After"),
+	}
+	var body models.Error
+	if err := json.Unmarshal(resp.Body, &body); err == nil && body.Message != "" {
+		e.Code = body.Code
+		e.Message = body.Message
+		e.Version = body.Version
+		// type was added to error responses after the schema in openapi.yaml
+		var extra struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(resp.Body, &extra) == nil {
+			e.Type = extra.Type
+		}
+	} else {
+		e.Message = strings.TrimSpace(string(resp.Body))
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	e.Hint = hint(e, resp.Request)
+	return e
+}
+
+// ErrorResult turns an error response into a tool error that carries the
+// parsed error as structured content next to a readable summary.
+func ErrorResult(resp *Response) *mcp.CallToolResult {
+	e := ParseError(resp)
+	return &mcp.CallToolResult{
+		IsError:           true,
+		Content:           []mcp.Content{mcp.NewTextContent(e.Summary())},
+		StructuredContent: e,
+	}
+}
+
+// Summary renders the error as a single paragraph for text-only clients.
+func (e *Error) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Appwrite error %d", e.Status)
+	if e.Type != "" {
+		fmt.Fprintf(&b, " (%s)", e.Type)
+	}
+	fmt.Fprintf(&b, ": %s", e.Message)
+	if e.Retryable {
+		b.WriteString(" [retryable]")
+	}
+	if e.Hint != "" {
+		fmt.Fprintf(&b, "\nHint: %s", e.Hint)
+	}
+	return b.String()
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// hint suggests a next step for the common failure modes.
+func hint(e *Error, r *Request) string {
+	var path string
+	var scope string
+	if r != nil {
+		path = r.Path
+		scope = r.Scope
+	}
+	switch e.Status {
+	case http.StatusBadRequest:
+		return "Check the arguments against the tool's input schema; the message names the invalid parameter."
+	case http.StatusUnauthorized:
+		if scope != "" {
+			return fmt.Sprintf("The request was not authorized. Make sure the project ID is right and the API key has the %q scope, or that the JWT is valid and not expired.", scope)
+		}
+		return "The request was not authorized. Make sure the project ID is right and the API key or JWT is valid."
+	case http.StatusForbidden:
+		return "The credentials are valid but not allowed to access this resource. Check the resource permissions or use a key with broader scopes."
+	case http.StatusNotFound:
+		if strings.HasPrefix(path, "/database/collections/") {
+			return "The collection or document does not exist. Call get_database_collections to list valid collection IDs, then get_database_collections_collectionId_documents for document IDs."
+		}
+		return "The resource does not exist. Use the matching list tool to look up a valid ID."
+	case http.StatusConflict:
+		return "A resource with the same ID or unique value (such as an email address) already exists. Fetch the existing resource or pick a different value."
+	case http.StatusTooManyRequests:
+		if e.RetryAfter != "" {
+			return fmt.Sprintf("Appwrite rate limit reached. Wait %s seconds before retrying.", e.RetryAfter)
+		}
+		return "Appwrite rate limit reached. Wait before retrying and reduce the request rate."
+	}
+	if e.Retryable {
+		return "Appwrite could not handle the request right now. Retrying later may succeed."
+	}
+	return ""
+}
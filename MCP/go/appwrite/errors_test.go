@@ -0,0 +1,188 @@
+package appwrite
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		status    int
+		body      string
+		header    http.Header
+		request   *Request
+		want      Error
+		hint      string // Substring of the hint
+		retryable bool
+	}{
+		{
+			name:   "numeric code",
+			status: 400,
+			body:   `{"message":"Invalid email","code":400,"type":"general_argument_invalid","version":"1.4.0"}`,
+			want:   Error{Status: 400, Code: "400", Type: "general_argument_invalid", Message: "Invalid email", Version: "1.4.0"},
+			hint:   "input schema",
+		},
+		{
+			name:    "string code",
+			status:  401,
+			body:    `{"message":"Missing scope","code":"user_unauthorized"}`,
+			request: &Request{Scope: "users.read"},
+			want:    Error{Status: 401, Code: "user_unauthorized", Message: "Missing scope"},
+			hint:    `"users.read" scope`,
+		},
+		{
+			name:   "null code",
+			status: 401,
+			body:   `{"message":"Unauthorized","code":null}`,
+			want:   Error{Status: 401, Message: "Unauthorized"},
+			hint:   "API key or JWT is valid",
+		},
+		{
+			name:   "forbidden",
+			status: 403,
+			body:   `{"message":"No permission"}`,
+			want:   Error{Status: 403, Message: "No permission"},
+			hint:   "resource permissions",
+		},
+		{
+			name:    "document not found",
+			status:  404,
+			body:    `{"message":"Document not found"}`,
+			request: &Request{Path: "/database/collections/c/documents/d"},
+			want:    Error{Status: 404, Message: "Document not found"},
+			hint:    "get_database_collections",
+		},
+		{
+			name:    "not found",
+			status:  404,
+			body:    `{"message":"User not found"}`,
+			request: &Request{Path: "/users/u"},
+			want:    Error{Status: 404, Message: "User not found"},
+			hint:    "matching list tool",
+		},
+		{
+			name:   "conflict",
+			status: 409,
+			body:   `{"message":"User already exists"}`,
+			want:   Error{Status: 409, Message: "User already exists"},
+			hint:   "already exists",
+		},
+		{
+			name:      "rate limited",
+			status:    429,
+			body:      `{"message":"Too many requests"}`,
+			header:    http.Header{"Retry-After": {"30"}},
+			want:      Error{Status: 429, Message: "Too many requests", RetryAfter: "30"},
+			hint:      "Wait 30 seconds",
+			retryable: true,
+		},
+		{
+			name:      "rate limited without Retry-After",
+			status:    429,
+			body:      `{"message":"Too many requests"}`,
+			want:      Error{Status: 429, Message: "Too many requests"},
+			hint:      "reduce the request rate",
+			retryable: true,
+		},
+		{
+			name:      "non-JSON body",
+			status:    502,
+			body:      "<html>Bad Gateway</html>\n",
+			want:      Error{Status: 502, Message: "<html>Bad Gateway</html>"},
+			hint:      "Retrying later",
+			retryable: true,
+		},
+		{
+			name:      "JSON without a message",
+			status:    503,
+			body:      `{"error":"down"}`,
+			want:      Error{Status: 503, Message: `{"error":"down"}`},
+			hint:      "Retrying later",
+			retryable: true,
+		},
+		{
+			name:      "empty body",
+			status:    500,
+			want:      Error{Status: 500, Message: "Internal Server Error"},
+			hint:      "Retrying later",
+			retryable: true,
+		},
+		{
+			name:   "not retryable server error",
+			status: 501,
+			body:   "not implemented",
+			want:   Error{Status: 501, Message: "not implemented"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			header := tc.header
+			if header == nil {
+				header = http.Header{}
+			}
+			e := ParseError(&Response{StatusCode: tc.status, Header: header, Body: []byte(tc.body), Request: tc.request})
+			if e.Retryable != tc.retryable {
+				t.Errorf("retryable = %v, want %v", e.Retryable, tc.retryable)
+			}
+			if tc.hint == "" && e.Hint != "" || !strings.Contains(e.Hint, tc.hint) {
+				t.Errorf("hint = %q, want it to contain %q", e.Hint, tc.hint)
+			}
+			got := *e
+			got.Hint, got.Retryable = "", false
+			if got != tc.want {
+				t.Errorf("error = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestErrorResult(t *testing.T) {
+	result := ErrorResult(&Response{
+		StatusCode: 429,
+		Header:     http.Header{"Retry-After": {"5"}},
+		Body:       []byte(`{"message":"Rate limit exceeded","code":429,"type":"general_rate_limit_exceeded","version":"1.4.0"}`),
+	})
+	if !result.IsError || len(result.Content) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	want := "Appwrite error 429 (general_rate_limit_exceeded): Rate limit exceeded [retryable]\nHint: Appwrite rate limit reached. Wait 5 seconds before retrying."
+	if text := result.Content[0].(mcp.TextContent).Text; text != want {
+		t.Errorf("summary = %q, want %q", text, want)
+	}
+
+	// Clients get the structured content as this JSON object
+	encoded, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	json.Unmarshal(encoded, &got)
+	wantJSON := map[string]any{
+		"status":     float64(429),
+		"code":       "429",
+		"type":       "general_rate_limit_exceeded",
+		"message":    "Rate limit exceeded",
+		"version":    "1.4.0",
+		"retryable":  true,
+		"retryAfter": "5",
+		"hint":       "Appwrite rate limit reached. Wait 5 seconds before retrying.",
+	}
+	if len(got) != len(wantJSON) {
+		t.Errorf("structured content = %s", encoded)
+	}
+	for key, val := range wantJSON {
+		if got[key] != val {
+			t.Errorf("structured content %s = %v, want %v", key, got[key], val)
+		}
+	}
+
+	// Optional fields are omitted, retryable is always present
+	encoded, _ = json.Marshal(ErrorResult(&Response{StatusCode: 404, Header: http.Header{}, Body: []byte("gone")}).StructuredContent)
+	if string(encoded) != `{"status":404,"message":"gone","retryable":false,"hint":"The resource does not exist. Use the matching list tool to look up a valid ID."}` {
+		t.Errorf("structured content = %s", encoded)
+	}
+}
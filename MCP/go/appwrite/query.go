@@ -0,0 +1,66 @@
+package appwrite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// QueryFromArgs builds the query string for the named tool arguments. Absent
+// arguments are skipped.
+func QueryFromArgs(args map[string]any, names ...string) url.Values {
+	query := url.Values{}
+	for _, name := range names {
+		if val, ok := args[name]; ok {
+			AddQuery(query, name, val)
+		}
+	}
+	return query
+}
+
+// AddQuery adds val under name the way Appwrite expects it: arrays use the
+// name[]=a&name[]=b form and numbers are written without a fractional part
+// unless they have one.
+func AddQuery(query url.Values, name string, val any) {
+	if items, ok := val.([]any); ok {
+		for _, item := range items {
+			query.Add(name+"[]", formatValue(item))
+		}
+		return
+	}
+	if items, ok := val.([]string); ok {
+		for _, item := range items {
+			query.Add(name+"[]", item)
+		}
+		return
+	}
+	query.Add(name, formatValue(val))
+}
+
+func formatValue(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case json.Number:
+		return v.String()
+	case map[string]any:
+		encoded, err := json.Marshal(v)
+		if err == nil {
+			return string(encoded)
+		}
+	}
+	return fmt.Sprint(val)
+}
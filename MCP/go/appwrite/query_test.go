@@ -0,0 +1,74 @@
+package appwrite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/appwrite/mcp-server/config"
+)
+
+func TestQueryFromArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"array", map[string]any{"queries": []any{"limit(1)", "offset(2)"}}, "queries%5B%5D=limit%281%29&queries%5B%5D=offset%282%29"},
+		{"string array", map[string]any{"queries": []string{"a", "b"}}, "queries%5B%5D=a&queries%5B%5D=b"},
+		{"empty array", map[string]any{"queries": []any{}}, ""},
+		{"reserved characters", map[string]any{"search": "a=b&$c+d e"}, "search=a%3Db%26%24c%2Bd+e"},
+		{"integer", map[string]any{"limit": float64(25)}, "limit=25"},
+		{"fraction", map[string]any{"quality": 0.5}, "quality=0.5"},
+		{"large integer", map[string]any{"limit": 1e21}, "limit=1000000000000000000000"},
+		{"negative", map[string]any{"offset": float64(-3)}, "offset=-3"},
+		{"int", map[string]any{"limit": 7}, "limit=7"},
+		{"json number", map[string]any{"limit": json.Number("12")}, "limit=12"},
+		{"numbers in an array", map[string]any{"ids": []any{float64(1), 2.5}}, "ids%5B%5D=1&ids%5B%5D=2.5"},
+		{"true", map[string]any{"download": true}, "download=true"},
+		{"false", map[string]any{"download": false}, "download=false"},
+		{"object", map[string]any{"filter": map[string]any{"a": 1}}, "filter=%7B%22a%22%3A1%7D"},
+		{"null", map[string]any{"search": nil}, "search="},
+		{"absent and unnamed", map[string]any{"other": "x"}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			names := make([]string, 0, len(tc.args))
+			for name := range tc.args {
+				if name != "other" {
+					names = append(names, name)
+				}
+			}
+			names = append(names, "missing")
+			if got := QueryFromArgs(tc.args, names...).Encode(); got != tc.want {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPathEscapedIDs(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.RequestURI
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	c := NewClient(&config.ClientConfig{})
+
+	for id, want := range map[string]string{
+		"a/b":      "/v1/users/a%2Fb/prefs",
+		"../admin": "/v1/users/..%2Fadmin/prefs",
+		"a b?c#d":  "/v1/users/a%20b%3Fc%23d/prefs",
+		"plain-id": "/v1/users/plain-id/prefs",
+	} {
+		if _, err := c.Do(context.Background(), &config.APIConfig{BaseURL: srv.URL + "/v1"}, &Request{Method: http.MethodGet, Path: "/users/" + url.PathEscape(id) + "/prefs"}); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%q: request URI %s, want %s", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package appwrite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Call performs r with the default client and converts the response into a
+// tool result. Successful responses are decoded into result, which should be a
+// pointer to the operation's response model, and returned as indented JSON.
+func Call(ctx context.Context, cfg *config.APIConfig, r *Request, result any) (*mcp.CallToolResult, error) {
+	resp, err := Default().Do(ctx, cfg, r)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+	}
+	return Result(resp, result)
+}
+
+// Result converts an upstream response into a tool result; see Call.
+func Result(resp *Response, result any) (*mcp.CallToolResult, error) {
+	if resp.StatusCode >= 400 {
+		return ErrorResult(resp), nil
+	}
+	if len(resp.Body) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Request succeeded with status %d", resp.StatusCode)), nil
+	}
+	// Numbers are kept as json.Number so untyped results don't lose precision
+	dec := json.NewDecoder(bytes.NewReader(resp.Body))
+	dec.UseNumber()
+	if err := dec.Decode(result); err != nil {
+		// Fallback to raw text if unmarshaling fails
+		return mcp.NewToolResultText(string(resp.Body)), nil
+	}
+
+	prettyJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+	}
+
+	return mcp.NewToolResultText(string(prettyJSON)), nil
+}
@@ -0,0 +1,86 @@
+package appwrite
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/appwrite/mcp-server/egress"
+)
+
+// retryPolicy decides whether and when a failed request is sent again.
+type retryPolicy struct {
+	max       int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// idempotent reports whether method can be repeated without side effects
+// beyond the first successful attempt.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// transient reports whether the outcome of an attempt is worth retrying:
+// network errors, rate limiting and server errors.
+func transient(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// A cancelled tool call, an expired deadline or a refused address
+		// must not be retried
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled) && !egress.IsBlocked(err)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// delay returns the backoff before retry number attempt (starting at 0), using
+// exponential backoff with full jitter. A Retry-After header takes precedence.
+// ok is false when the server asks for a longer wait than maxDelay.
+func (p retryPolicy) delay(attempt int, resp *http.Response) (d time.Duration, ok bool) {
+	if resp != nil {
+		if after, found := retryAfter(resp.Header.Get("Retry-After")); found {
+			return after, after <= p.maxDelay
+		}
+	}
+	backoff := p.baseDelay << attempt
+	if backoff <= 0 || backoff > p.maxDelay {
+		backoff = p.maxDelay
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	return rand.N(backoff) + 1, true
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func retryAfter(val string) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(val); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(val); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d unless ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package appwrite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/config"
+)
+
+// flaky answers with the given statuses in turn, then 200, counting the
+// requests it receives.
+func flaky(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(count.Add(1))
+		io.Copy(io.Discard, r.Body)
+		if n <= len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &count
+}
+
+func retryClient(maxDelay time.Duration) *Client {
+	return NewClient(&config.ClientConfig{RetryMax: 3, RetryBaseDelay: time.Millisecond, RetryMaxDelay: maxDelay})
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	for _, status := range []int{http.StatusBadGateway, http.StatusTooManyRequests} {
+		srv, count := flaky(t, nil, status, status)
+		resp, err := retryClient(time.Second).Do(context.Background(), &config.APIConfig{BaseURL: srv.URL}, &Request{Method: http.MethodGet, Path: "/"})
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Errorf("%d: status %v, err %v", status, resp, err)
+		}
+		if n := count.Load(); n != 3 {
+			t.Errorf("%d: %d attempts, want 3", status, n)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv, count := flaky(t, http.Header{"Retry-After": {"1"}}, http.StatusTooManyRequests)
+	start := time.Now()
+	resp, err := retryClient(2*time.Second).Do(context.Background(), &config.APIConfig{BaseURL: srv.URL}, &Request{Method: http.MethodGet, Path: "/"})
+	if err != nil || resp.StatusCode != http.StatusOK || count.Load() != 2 {
+		t.Fatalf("resp %v, err %v, %d attempts", resp, err, count.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, before Retry-After", elapsed)
+	}
+
+	// A longer wait than RetryMaxDelay is handed back to the caller
+	srv, count = flaky(t, http.Header{"Retry-After": {"30"}}, http.StatusTooManyRequests)
+	start = time.Now()
+	resp, err = retryClient(time.Second).Do(context.Background(), &config.APIConfig{BaseURL: srv.URL}, &Request{Method: http.MethodGet, Path: "/"})
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests || count.Load() != 1 {
+		t.Fatalf("resp %v, err %v, %d attempts", resp, err, count.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("waited %s for a Retry-After beyond RetryMaxDelay", elapsed)
+	}
+}
+
+func TestNoRetryForNonIdempotentOrStreamed(t *testing.T) {
+	for name, r := range map[string]*Request{
+		"POST":     {Method: http.MethodPost, Path: "/", JSON: map[string]any{"a": 1}},
+		"streamed": {Method: http.MethodPut, Path: "/", Body: strings.NewReader("data")},
+	} {
+		srv, count := flaky(t, nil, http.StatusBadGateway)
+		resp, err := retryClient(time.Second).Do(context.Background(), &config.APIConfig{BaseURL: srv.URL}, r)
+		if err != nil || resp.StatusCode != http.StatusBadGateway || count.Load() != 1 {
+			t.Errorf("%s: resp %v, err %v, %d attempts", name, resp, err, count.Load())
+		}
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	for val, want := range map[string]time.Duration{"0": 0, "3": 3 * time.Second} {
+		if got, ok := retryAfter(val); !ok || got != want {
+			t.Errorf("retryAfter(%q) = %s, %v", val, got, ok)
+		}
+	}
+	if got, ok := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || got < 59*time.Minute {
+		t.Errorf("HTTP date: %s, %v", got, ok)
+	}
+	for _, val := range []string{"", "-1", "soon"} {
+		if _, ok := retryAfter(val); ok {
+			t.Errorf("retryAfter(%q) accepted", val)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package appwrite
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/appwrite/mcp-server/config"
+)
+
+// scheme mirrors one entry of components.securitySchemes in openapi.yaml.
+type scheme struct {
+	header string
+	env    string
+	value  func(cfg *config.APIConfig) string
+}
+
+var schemes = map[string]scheme{
+	"Project": {"X-Appwrite-Project", "APPWRITE_PROJECT", func(cfg *config.APIConfig) string { return cfg.Project }},
+	"Key":     {"X-Appwrite-Key", "APPWRITE_KEY", func(cfg *config.APIConfig) string { return cfg.Key }},
+	"JWT":     {"X-Appwrite-JWT", "APPWRITE_JWT", func(cfg *config.APIConfig) string { return cfg.JWT }},
+	"Locale":  {"X-Appwrite-Locale", "APPWRITE_LOCALE", func(cfg *config.APIConfig) string { return cfg.Locale }},
+}
+
+// applySecurity sets the headers for every scheme an operation lists in its
+// security block. The project is mandatory whenever it is listed, and at least
+// one of the listed credentials (Key or JWT) has to be configured; Appwrite
+// accepts either. Locale is optional and sent whenever it is configured.
+func applySecurity(h http.Header, cfg *config.APIConfig, security []string) error {
+	var credentials []string
+	haveCredential := false
+	for _, name := range security {
+		s, ok := schemes[name]
+		if !ok {
+			return fmt.Errorf("unknown security scheme %q", name)
+		}
+		val := s.value(cfg)
+		switch name {
+		case "Project":
+			if val == "" {
+				return fmt.Errorf("this operation requires a project ID; set %s or the %s header", s.env, s.header)
+			}
+		case "Key", "JWT":
+			credentials = append(credentials, fmt.Sprintf("%s (%s header)", s.env, s.header))
+			haveCredential = haveCredential || val != ""
+		}
+		if val != "" {
+			h.Set(s.header, val)
+		}
+	}
+	if len(credentials) > 0 && !haveCredential {
+		return fmt.Errorf("this operation requires credentials; set %s", strings.Join(credentials, " or "))
+	}
+	if locale := schemes["Locale"]; cfg.Locale != "" {
+		h.Set(locale.header, cfg.Locale)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package appwrite
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/appwrite/mcp-server/config"
+)
+
+func TestApplySecurity(t *testing.T) {
+	all := []string{"JWT", "Key", "Project"}
+	for _, tc := range []struct {
+		name     string
+		cfg      config.APIConfig
+		security []string
+		want     http.Header
+		err      string
+	}{
+		{
+			name:     "key",
+			cfg:      config.APIConfig{Project: "p", Key: "k"},
+			security: all,
+			want:     http.Header{"X-Appwrite-Project": {"p"}, "X-Appwrite-Key": {"k"}},
+		},
+		{
+			name:     "jwt",
+			cfg:      config.APIConfig{Project: "p", JWT: "j"},
+			security: all,
+			want:     http.Header{"X-Appwrite-Project": {"p"}, "X-Appwrite-Jwt": {"j"}},
+		},
+		{
+			name:     "key and jwt",
+			cfg:      config.APIConfig{Project: "p", Key: "k", JWT: "j"},
+			security: all,
+			want:     http.Header{"X-Appwrite-Project": {"p"}, "X-Appwrite-Key": {"k"}, "X-Appwrite-Jwt": {"j"}},
+		},
+		{
+			name:     "locale",
+			cfg:      config.APIConfig{Project: "p", Key: "k", Locale: "de"},
+			security: all,
+			want:     http.Header{"X-Appwrite-Project": {"p"}, "X-Appwrite-Key": {"k"}, "X-Appwrite-Locale": {"de"}},
+		},
+		{
+			name:     "locale not listed",
+			cfg:      config.APIConfig{Project: "p", Locale: "de"},
+			security: []string{"Project"},
+			want:     http.Header{"X-Appwrite-Project": {"p"}, "X-Appwrite-Locale": {"de"}},
+		},
+		{
+			name:     "only the listed schemes",
+			cfg:      config.APIConfig{Project: "p", Key: "k", JWT: "j"},
+			security: []string{"Project", "JWT"},
+			want:     http.Header{"X-Appwrite-Project": {"p"}, "X-Appwrite-Jwt": {"j"}},
+		},
+		{
+			name: "no security",
+			cfg:  config.APIConfig{Project: "p", Key: "k"},
+			want: http.Header{},
+		},
+		{
+			name:     "missing project",
+			cfg:      config.APIConfig{Key: "k"},
+			security: all,
+			err:      "requires a project ID; set APPWRITE_PROJECT or the X-Appwrite-Project header",
+		},
+		{
+			name:     "no credentials",
+			cfg:      config.APIConfig{Project: "p", Locale: "de"},
+			security: all,
+			err:      "requires credentials; set APPWRITE_JWT (X-Appwrite-JWT header) or APPWRITE_KEY (X-Appwrite-Key header)",
+		},
+		{
+			name:     "unknown scheme",
+			cfg:      config.APIConfig{Project: "p"},
+			security: []string{"Session"},
+			err:      `unknown security scheme "Session"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			err := applySecurity(h, &tc.cfg, tc.security)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("err = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(h, tc.want) {
+				t.Errorf("headers = %v, want %v", h, tc.want)
+			}
+		})
+	}
+}
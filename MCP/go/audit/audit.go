@@ -0,0 +1,229 @@
+// Package audit keeps an append-only JSON Lines record of tool calls: who
+// called which tool with which arguments, the Appwrite request it made and
+// the resource it affected. Secrets in arguments are redacted.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Entry is one line of the audit log.
+type Entry struct {
+	Time       time.Time      `json:"time"`
+	Client     string         `json:"client,omitempty"`  // Label of the authenticated client
+	Session    string         `json:"session,omitempty"` // MCP session ID
+	Profile    string         `json:"profile,omitempty"`
+	Tool       string         `json:"tool"`
+	ReadOnly   bool           `json:"read_only"`
+	Arguments  map[string]any `json:"arguments,omitempty"` // Redacted
+	Method     string         `json:"method,omitempty"`    // Of the last Appwrite request
+	Path       string         `json:"path,omitempty"`      // Of the last Appwrite request, without query
+	Status     int            `json:"status,omitempty"`    // HTTP status of the last Appwrite response
+	Result     string         `json:"result"`              // ok or error
+	Error      string         `json:"error,omitempty"`     // Redacted
+	ResourceID string         `json:"resource_id,omitempty"`
+	DurationMS float64        `json:"duration_ms"`
+}
+
+// Log appends entries to a file, rotating it once it would grow beyond
+// MaxBytes. Rotated files get the suffixes .1 (newest) to .MaxFiles; older
+// ones are removed.
+type Log struct {
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Open opens the audit log at path for appending. A maxBytes of 0 disables
+// rotation; otherwise at least one rotated file must be kept, so rotating
+// never discards the entries just written.
+func Open(path string, maxBytes int64, maxFiles int) (*Log, error) {
+	if maxBytes > 0 && maxFiles < 1 {
+		return nil, fmt.Errorf("AUDIT_LOG_MAX_FILES must be at least 1 when AUDIT_LOG_MAX_BYTES is set, got %d", maxFiles)
+	}
+	l := &Log{path: path, maxBytes: maxBytes, maxFiles: maxFiles}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// Write appends e as a single line.
+func (l *Log) Write(e *Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return errors.New("audit log is closed")
+	}
+	if l.maxBytes > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("rotate audit log: %v", err)
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// rotate shifts the rotated files up by one, dropping the oldest, and
+// starts a new file.
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+	os.Remove(fmt.Sprintf("%s.%d", l.path, l.maxFiles))
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+// Close closes the file. Later writes fail.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+type contextKey struct{}
+
+// Upstream records an Appwrite request made by the tool call in ctx. Only
+// the last request of a call ends up in its entry.
+func Upstream(ctx context.Context, method, path string, status int) {
+	if e, ok := ctx.Value(contextKey{}).(*Entry); ok {
+		e.Method, e.Path, e.Status = method, path, status
+	}
+}
+
+var defaultLog atomic.Pointer[Log]
+
+// SetDefault sets the log Instrument writes to. Until it is called, tool
+// calls are not audited.
+func SetDefault(l *Log) {
+	defaultLog.Store(l)
+}
+
+// Instrument wraps the handler of tool so every call is written to the
+// default log. Entries that cannot be written are reported in the server
+// log; the call itself is not affected.
+func Instrument(tool models.Tool) models.Tool {
+	name := tool.Definition.Name
+	readOnly := tool.Definition.Annotations.ReadOnlyHint != nil && *tool.Definition.Annotations.ReadOnlyHint
+	next := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		l := defaultLog.Load()
+		if l == nil {
+			return next(ctx, request)
+		}
+		args := request.GetArguments()
+		e := &Entry{
+			Time:      time.Now().UTC(),
+			Tool:      name,
+			ReadOnly:  readOnly,
+			Arguments: redact.Args(args),
+		}
+		if id, ok := auth.FromContext(ctx); ok {
+			e.Client, e.Profile = id.Label, id.Profile
+		}
+		if cfg := config.FromContext(ctx, nil); cfg != nil && cfg.Profile != "" {
+			e.Profile = cfg.Profile
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			e.Session = session.SessionID()
+		}
+
+		result, err := next(context.WithValue(ctx, contextKey{}, e), request)
+		e.DurationMS = float64(time.Since(e.Time).Microseconds()) / 1000
+		switch {
+		case err != nil:
+			e.Result, e.Error = "error", redact.Echoes(err.Error(), args)
+		case result != nil && result.IsError:
+			e.Result, e.Error = "error", redact.Echoes(models.ResultText(result), args)
+		default:
+			e.Result = "ok"
+			e.ResourceID = resourceID(result, e.Path, args)
+		}
+		if werr := l.Write(e); werr != nil {
+			slog.ErrorContext(ctx, "Failed to write audit log entry", "tool", name, "error", werr)
+		}
+		return result, err
+	}
+	return tool
+}
+
+// resourceID returns the $id of the resource in a successful result or,
+// for results without one such as deletions, the last path segment that is
+// an ID argument of the call.
+func resourceID(result *mcp.CallToolResult, path string, args map[string]any) string {
+	if result != nil {
+		var body struct {
+			ID string `json:"$id"`
+		}
+		if json.Unmarshal([]byte(models.ResultText(result)), &body) == nil && body.ID != "" {
+			return body.ID
+		}
+	}
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment, err := url.PathUnescape(segments[i])
+		if err != nil || segment == "" {
+			continue
+		}
+		for name, val := range args {
+			if s, ok := val.(string); ok && s == segment && strings.HasSuffix(name, "Id") {
+				return segment
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path, 300, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	for i := 0; i < 20; i++ {
+		if err := l.Write(&Entry{Tool: "get_users", Result: "ok"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 300 {
+			t.Errorf("%s: %d bytes, limit is 300", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("kept more than 2 rotated files: %v", err)
+	}
+}
+
+func TestRotationKeepsOneFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if _, err := Open(path, 300, 0); err == nil || !strings.Contains(err.Error(), "AUDIT_LOG_MAX_FILES") {
+		t.Errorf("Open with rotation and no rotated files: err = %v", err)
+	}
+
+	l, err := Open(path, 300, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	for i := 0; i < 20; i++ {
+		if err := l.Write(&Entry{Tool: "get_users", Result: "ok"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, err := os.Stat(path + ".1"); err != nil || info.Size() == 0 {
+		t.Errorf("rotated file not kept: %v", err)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("kept more than 1 rotated file: %v", err)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetDefault(l)
+	defer SetDefault(nil)
+
+	create := Instrument(models.Tool{
+		Definition: mcp.NewTool("post_users"),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			Upstream(ctx, "POST", "/users", 201)
+			return mcp.NewToolResultText(`{"$id": "user-1", "name": "Ada"}`), nil
+		},
+	})
+	remove := Instrument(models.Tool{
+		Definition: mcp.NewTool("delete_users_userId", mcp.WithReadOnlyHintAnnotation(false)),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			Upstream(ctx, "DELETE", "/users/user-1", 204)
+			return mcp.NewToolResultText("Request succeeded with status 204"), nil
+		},
+	})
+	failing := Instrument(models.Tool{
+		Definition: mcp.NewTool("patch_account_password"),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			Upstream(ctx, "PATCH", "/account/password", 400)
+			return mcp.NewToolResultError("password hunter2-hunter2 is too weak"), nil
+		},
+	})
+
+	call := func(tool models.Tool, args map[string]any) {
+		var request mcp.CallToolRequest
+		request.Params.Arguments = args
+		tool.Handler(context.Background(), request)
+	}
+	call(create, map[string]any{"userId": "unique()", "email": "ada@example.com", "password": "hunter2-hunter2"})
+	call(remove, map[string]any{"userId": "user-1"})
+	call(failing, map[string]any{"password": "hunter2-hunter2"})
+	l.Close()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("password in audit log:\n%s", data)
+	}
+	entries := readEntries(t, path)
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	if e := entries[0]; e.Tool != "post_users" || e.Method != "POST" || e.Path != "/users" || e.Status != 201 || e.ResourceID != "user-1" || e.Result != "ok" || e.Arguments["email"] != "ada@example.com" {
+		t.Errorf("create: %+v", e)
+	}
+	if e := entries[1]; e.ResourceID != "user-1" || e.Status != 204 || e.ReadOnly {
+		t.Errorf("delete: %+v", e)
+	}
+	if e := entries[2]; e.Result != "error" || e.Status != 400 || e.Error == "" || e.ResourceID != "" {
+		t.Errorf("failure: %+v", e)
+	}
+}
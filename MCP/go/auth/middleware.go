@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// MetadataPath is where the OAuth protected resource metadata (RFC 9728) is
+// published.
+const MetadataPath = "/.well-known/oauth-protected-resource"
+
+type contextKey struct{}
+
+// FromContext returns the client the request in ctx authenticated as. It
+// reports false if the endpoint is not protected.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
+
+// Require wraps next so only requests presenting one of tokens, either as
+// "Authorization: Bearer <token>" or in an X-API-Key header, get through.
+// Others get 401 with a WWW-Authenticate header pointing at the resource
+// metadata.
+func Require(tokens *Tokens, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-API-Key")
+		if scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			token = strings.TrimSpace(value)
+		}
+		if token == "" {
+			unauthorized(w, `Bearer resource_metadata="`+metadataURL(r)+`"`)
+			return
+		}
+		id, ok := tokens.Lookup(token)
+		if !ok {
+			slog.WarnContext(r.Context(), "Rejected request with unknown token", "remote_addr", r.RemoteAddr)
+			unauthorized(w, `Bearer error="invalid_token", resource_metadata="`+metadataURL(r)+`"`)
+			return
+		}
+		slog.DebugContext(r.Context(), "Authenticated request", "method", r.Method, "path", r.URL.Path, "client", id.Label)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, id)))
+	})
+}
+
+func unauthorized(w http.ResponseWriter, challenge string) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
+
+// Metadata describes the MCP endpoint as an OAuth protected resource, so
+// clients can discover how to authenticate.
+type Metadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers,omitempty"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	ResourceName           string   `json:"resource_name,omitempty"`
+}
+
+// MetadataHandler serves the protected resource metadata. resource is the
+// public URL of the MCP endpoint; if it is empty it is derived from the
+// request and path.
+func MetadataHandler(resource, path string, authorizationServers []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md := Metadata{
+			Resource:               resource,
+			AuthorizationServers:   authorizationServers,
+			BearerMethodsSupported: []string{"header"},
+			ResourceName:           "Appwrite MCP Server",
+		}
+		if md.Resource == "" {
+			md.Resource = origin(r) + path
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(md)
+	})
+}
+
+func metadataURL(r *http.Request) string {
+	return origin(r) + MetadataPath
+}
+
+// origin returns the scheme and host the client used to reach the server,
+// honouring X-Forwarded-Proto from a TLS-terminating proxy.
+func origin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	tokens := &Tokens{}
+	tokens.Add(Identity{Label: "ci"}, "good-token")
+	h := Require(tokens, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := FromContext(r.Context())
+		w.Write([]byte(id.Label))
+	}))
+
+	for name, header := range map[string][2]string{
+		"missing":        {},
+		"unknown bearer": {"Authorization", "Bearer bad-token"},
+		"unknown key":    {"X-API-Key", "bad-token"},
+		"other scheme":   {"Authorization", "Basic good-token"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "http://mcp.example.com/mcp", nil)
+		if header[0] != "" {
+			req.Header.Set(header[0], header[1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		challenge := rec.Header().Get("WWW-Authenticate")
+		if rec.Code != http.StatusUnauthorized || !strings.Contains(challenge, `resource_metadata="http://mcp.example.com/.well-known/oauth-protected-resource"`) {
+			t.Errorf("%s: status %d, challenge %q", name, rec.Code, challenge)
+		}
+		if strings.HasPrefix(name, "unknown") && !strings.Contains(challenge, `error="invalid_token"`) {
+			t.Errorf("%s: challenge %q lacks invalid_token", name, challenge)
+		}
+	}
+
+	for name, header := range map[string][2]string{
+		"bearer":  {"Authorization", "Bearer good-token"},
+		"api key": {"X-API-Key", "good-token"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set(header[0], header[1])
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Body.String() != "ci" {
+			t.Errorf("%s: status %d, body %q", name, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestMetadataHandler(t *testing.T) {
+	for _, tc := range []struct {
+		resource, want string
+		header         http.Header
+	}{
+		{"", "https://mcp.example.com/mcp", http.Header{"X-Forwarded-Proto": {"https"}}},
+		{"https://public.example.com/mcp", "https://public.example.com/mcp", nil},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://mcp.example.com"+MetadataPath, nil)
+		for k, v := range tc.header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		MetadataHandler(tc.resource, "/mcp", []string{"https://auth.example.com"}).ServeHTTP(rec, req)
+
+		var md Metadata
+		if err := json.Unmarshal(rec.Body.Bytes(), &md); err != nil {
+			t.Fatal(err)
+		}
+		if md.Resource != tc.want || len(md.AuthorizationServers) != 1 || md.AuthorizationServers[0] != "https://auth.example.com" ||
+			len(md.BearerMethodsSupported) != 1 || md.BearerMethodsSupported[0] != "header" {
+			t.Errorf("metadata = %+v", md)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+	}
+}
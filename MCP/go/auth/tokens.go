@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Identity is the client a token belongs to.
+type Identity struct {
+	Label   string // Name the client appears under in logs
+	Profile string // Vault profile the client's requests use, if any
+}
+
+// Tokens is the set of bearer tokens and API keys accepted on the MCP
+// endpoint. Only SHA-256 digests are kept in memory. It is safe for
+// concurrent use.
+type Tokens struct {
+	mu      sync.RWMutex
+	entries []entry
+}
+
+type entry struct {
+	id     Identity
+	digest [sha256.Size]byte
+}
+
+var errDuplicate = errors.New("duplicate token")
+
+// Add accepts token for the client id.
+func (t *Tokens) Add(id Identity, token string) error {
+	if token == "" {
+		return errors.New("empty token")
+	}
+	digest := sha256.Sum256([]byte(token))
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, e := range t.entries {
+		if e.digest == digest {
+			return errDuplicate
+		}
+	}
+	t.entries = append(t.entries, entry{id: id, digest: digest})
+	return nil
+}
+
+// LoadFile reads a token file into t. Each non-empty line that does not
+// start with # holds one token, optionally preceded by a label and a colon:
+//
+//	# label:token
+//	support-bot:3f9c0e...
+//	ci:7a41d2...
+//
+// Labels appear in logs instead of the token. Tokens without a label are
+// logged as token-<line number>.
+func (t *Tokens) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	added := 0
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		label, token, ok := strings.Cut(text, ":")
+		if !ok {
+			label, token = fmt.Sprintf("token-%d", line), text
+		}
+		if err := t.Add(Identity{Label: strings.TrimSpace(label)}, strings.TrimSpace(token)); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		added++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if added == 0 {
+		return fmt.Errorf("%s: no tokens", path)
+	}
+	return nil
+}
+
+// Lookup returns the client token belongs to. Every entry is compared in
+// constant time, so the time taken does not reveal which one matched or how
+// much of a token was right.
+func (t *Tokens) Lookup(token string) (Identity, bool) {
+	digest := sha256.Sum256([]byte(token))
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var id Identity
+	found := 0
+	for _, e := range t.entries {
+		match := subtle.ConstantTimeCompare(digest[:], e.digest[:])
+		if match == 1 {
+			id = e.id
+		}
+		found |= match
+	}
+	return id, found == 1
+}
+
+// Len returns the number of tokens.
+func (t *Tokens) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.entries)
+}
+
+// Replace makes t accept exactly the tokens of other, e.g. after the token
+// files were reloaded.
+func (t *Tokens) Replace(other *Tokens) {
+	other.mu.RLock()
+	entries := append([]entry{}, other.entries...)
+	other.mu.RUnlock()
+	t.mu.Lock()
+	t.entries = entries
+	t.mu.Unlock()
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	tokens := &Tokens{}
+	err := tokens.LoadFile(writeFile(t, "# label:token\n\nsupport-bot: secret-one\n  secret-two  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", tokens.Len())
+	}
+	if id, ok := tokens.Lookup("secret-one"); !ok || id.Label != "support-bot" {
+		t.Errorf("labelled token: %+v, %v", id, ok)
+	}
+	if id, ok := tokens.Lookup("secret-two"); !ok || id.Label != "token-4" {
+		t.Errorf("unlabelled token: %+v, %v", id, ok)
+	}
+	for _, token := range []string{"", "secret", "# label"} {
+		if _, ok := tokens.Lookup(token); ok {
+			t.Errorf("Lookup(%q) matched", token)
+		}
+	}
+
+	for name, content := range map[string]string{
+		"duplicate": "a:same\nb:same\n",
+		"empty":     "# only comments\n\n",
+		"no token":  "label:\n",
+	} {
+		if err := (&Tokens{}).LoadFile(writeFile(t, content)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/appwrite/mcp-server/openapi"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolData feeds toolTemplate.
+type toolData struct {
+	Name        string
+	ToolName    string
+	Service     string
+	Description string
+	Method      string
+	Path        string
+	PathParams  []string
+	QueryParams []string
+	HasBody     bool
+	NeedArgs    bool
+	Binary      bool
+	Result      string
+	Security    []string
+	Scope       string
+	Params      []string
+	Annotations mcp.ToolAnnotation
+}
+
+type modelData struct {
+	Name   string
+	Map    bool
+	Fields []fieldData
+}
+
+type fieldData struct {
+	Name    string
+	Type    string
+	JSON    string
+	Comment string
+}
+
+type registryEntry struct {
+	Service string
+	Name    string
+	Binary  bool // Registered together with its save_ tool
+}
+
+// generator renders every output file for a spec into memory, keyed by the
+// path relative to the output directory.
+type generator struct {
+	spec     *openapi.Spec
+	out      string
+	files    map[string][]byte
+	registry []registryEntry
+	skipped  []string
+}
+
+var createFunc = regexp.MustCompile(`(?m)^func Create(\w+)Tool\(`)
+
+func (g *generator) run() error {
+	g.files = map[string][]byte{}
+	for _, op := range g.spec.Operations() {
+		if err := g.operation(op); err != nil {
+			return fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+	}
+	if err := g.models(); err != nil {
+		return err
+	}
+	return g.registryFile()
+}
+
+func (g *generator) operation(op *openapi.Operation) error {
+	name := funcName(op.OperationID)
+	rel := filepath.Join("tools", op.Service(), strings.ToLower(op.OperationID)+".go")
+
+	// Files without the generated header are hand-written overrides; keep
+	// them and register whatever tool they define.
+	if existing, err := os.ReadFile(filepath.Join(g.out, rel)); err == nil && !bytes.HasPrefix(existing, []byte(header)) {
+		m := createFunc.FindSubmatch(existing)
+		if m == nil {
+			return fmt.Errorf("hand-written %s defines no Create...Tool function", rel)
+		}
+		g.registry = append(g.registry, registryEntry{Service: op.Service(), Name: string(m[1])})
+		g.skipped = append(g.skipped, fmt.Sprintf("%s %s (%s): hand-written in %s", op.Method, op.Path, op.OperationID, rel))
+		return nil
+	}
+	if ct := op.BodyContentType(); ct != "" && ct != "application/json" {
+		g.skipped = append(g.skipped, fmt.Sprintf("%s %s (%s): unmapped, %s request bodies need a hand-written tool in %s", op.Method, op.Path, op.OperationID, ct, rel))
+		return nil
+	}
+
+	params := op.Params(g.spec)
+	data := toolData{
+		Name:        name,
+		ToolName:    op.ToolName(),
+		Service:     op.Service(),
+		Description: op.Summary,
+		Method:      op.Method,
+		Path:        pathExpr(op.Path),
+		PathParams:  openapi.ParamsIn(params, "path"),
+		QueryParams: openapi.ParamsIn(params, "query"),
+		HasBody:     len(openapi.ParamsIn(params, "body")) > 0,
+		Binary:      op.Binary(),
+		Result:      "map[string]interface{}",
+		Security:    op.SecuritySchemes(),
+		Scope:       op.Appwrite.Scope,
+		Annotations: op.Annotations(g.spec),
+	}
+	data.NeedArgs = len(data.PathParams)+len(data.QueryParams) > 0 || data.HasBody
+	if model := op.ResponseModel(); model != "" {
+		data.Result = "models." + typeName(model)
+	}
+	for _, p := range params {
+		data.Params = append(data.Params, paramOption(p))
+	}
+
+	src, err := render(toolTemplate, data)
+	if err != nil {
+		return err
+	}
+	g.files[rel] = src
+	g.registry = append(g.registry, registryEntry{Service: op.Service(), Name: name, Binary: data.Binary})
+	return nil
+}
+
+func (g *generator) models() error {
+	var models []modelData
+	for _, name := range openapi.SortedKeys(g.spec.Components.Schemas) {
+		schema := g.spec.Components.Schemas[name]
+		model := modelData{Name: typeName(name)}
+		if len(schema.Properties) == 0 && schema.AdditionalProperties != nil {
+			model.Map = true
+			models = append(models, model)
+			continue
+		}
+		for _, prop := range openapi.SortedKeys(schema.Properties) {
+			field := fieldName(prop)
+			switch field {
+			case "Default", "Type":
+				field += "Field"
+			}
+			model.Fields = append(model.Fields, fieldData{
+				Name:    field,
+				Type:    g.goType(schema.Properties[prop]),
+				JSON:    prop,
+				Comment: oneLine(schema.Properties[prop].Description),
+			})
+		}
+		if additional(schema) {
+			model.Fields = append(model.Fields, fieldData{
+				Name:    "Data",
+				Type:    "map[string]interface{}",
+				JSON:    "-",
+				Comment: "Additional properties not covered by the schema.",
+			})
+		}
+		models = append(models, model)
+	}
+	src, err := render(modelsTemplate, models)
+	if err != nil {
+		return err
+	}
+	g.files[filepath.Join("models", "models.go")] = src
+	return nil
+}
+
+func (g *generator) registryFile() error {
+	sort.Slice(g.registry, func(i, j int) bool {
+		if g.registry[i].Service != g.registry[j].Service {
+			return g.registry[i].Service < g.registry[j].Service
+		}
+		return g.registry[i].Name < g.registry[j].Name
+	})
+	var services []string
+	for _, e := range g.registry {
+		if len(services) == 0 || services[len(services)-1] != e.Service {
+			services = append(services, e.Service)
+		}
+	}
+	binary := slices.ContainsFunc(g.registry, func(e registryEntry) bool { return e.Binary })
+	src, err := render(registryTemplate, map[string]any{"Services": services, "Tools": g.registry, "Binary": binary})
+	if err != nil {
+		return err
+	}
+	g.files["registry.go"] = src
+	return nil
+}
+
+// goType maps a property schema onto the Go type used in models.go.
+func (g *generator) goType(s *openapi.Schema) string {
+	if s.Ref != "" {
+		return typeName(openapi.RefName(s.Ref))
+	}
+	switch s.Type {
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if s.Items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(s.Items)
+	case "object":
+		// Appwrite describes key-value objects as an object whose items point
+		// at a free-form schema; those map onto the named map type.
+		if s.Items != nil && s.Items.Ref != "" {
+			if item := g.spec.Resolve(s.Items); item != nil && len(item.Properties) == 0 && additional(item) {
+				return typeName(openapi.RefName(s.Items.Ref))
+			}
+		}
+		return "map[string]interface{}"
+	}
+	return "string"
+}
+
+func additional(s *openapi.Schema) bool {
+	switch v := s.AdditionalProperties.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		return true
+	}
+	return false
+}
+
+func paramOption(p openapi.Param) string {
+	desc := oneLine(p.Description)
+	if p.In == "body" {
+		desc = "Input parameter: " + desc
+	}
+	opts := []string{quote(p.Name)}
+	if p.Required {
+		opts = append(opts, "mcp.Required()")
+	}
+	opts = append(opts, "mcp.Description("+quote(desc)+")")
+	if p.Type == "array" && p.ItemsType == "string" {
+		opts = append(opts, "mcp.WithStringItems()")
+	}
+	kind := map[string]string{
+		"string":  "WithString",
+		"number":  "WithNumber",
+		"boolean": "WithBoolean",
+		"array":   "WithArray",
+		"object":  "WithObject",
+	}[p.Type]
+	return "mcp." + kind + "(" + strings.Join(opts, ", ") + ")"
+}
+
+// pathExpr turns /teams/{teamId} into a fmt.Sprintf call that escapes each
+// path parameter.
+func pathExpr(path string) string {
+	var args []string
+	format := regexp.MustCompile(`\{(\w+)\}`).ReplaceAllStringFunc(path, func(m string) string {
+		args = append(args, "url.PathEscape("+m[1:len(m)-1]+")")
+		return "%s"
+	})
+	if len(args) == 0 {
+		return quote(path)
+	}
+	return "fmt.Sprintf(" + quote(format) + ", " + strings.Join(args, ", ") + ")"
+}
+
+// funcName follows the naming of the existing tools: the lower-cased
+// operation ID with its first letter capitalised.
+func funcName(operationID string) string {
+	lower := strings.ToLower(operationID)
+	return strings.ToUpper(lower[:1]) + lower[1:]
+}
+
+func typeName(schema string) string {
+	return strings.ToUpper(schema[:1]) + schema[1:]
+}
+
+func fieldName(prop string) string {
+	return funcName(strings.TrimPrefix(prop, "$"))
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// render executes t and gofmts the result.
+func render(t *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w\n%s", t.Name(), err, buf.Bytes())
+	}
+	return src, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+const testSpec = "testdata/spec.yaml"
+
+func TestGolden(t *testing.T) {
+	var stderr bytes.Buffer
+	out := t.TempDir()
+	if code := run([]string{"-spec", testSpec, "-out", out}, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, &stderr)
+	}
+	if !strings.Contains(stderr.String(), "skipped POST /storage/files (storageCreateFile): unmapped, multipart/form-data request bodies") {
+		t.Errorf("multipart operation not reported as skipped: %s", &stderr)
+	}
+
+	want := []string{
+		"models/models.go",
+		"registry.go",
+		"tools/avatars/avatarsgetqr.go",
+		"tools/teams/teamscreatemembership.go",
+		"tools/teams/teamsgetmemberships.go",
+	}
+	var got []string
+	filepath.WalkDir(out, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(out, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("generated files:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	for _, rel := range want {
+		src, err := os.ReadFile(filepath.Join(out, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", "golden", rel+".txt")
+		if *update {
+			os.MkdirAll(filepath.Dir(golden), 0o755)
+			if err := os.WriteFile(golden, src, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%v; run go test ./cmd/mcpgen -update", err)
+		}
+		if !bytes.Equal(src, expected) {
+			t.Errorf("%s differs from %s; run go test ./cmd/mcpgen -update and review the diff\n%s", rel, golden, src)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	out := t.TempDir()
+	check := func() (int, string) {
+		var stderr bytes.Buffer
+		code := run([]string{"-spec", testSpec, "-out", out, "-check"}, &stderr)
+		return code, stderr.String()
+	}
+	if code, _ := check(); code != 1 {
+		t.Errorf("check of an empty tree: exit code %d", code)
+	}
+	var stderr bytes.Buffer
+	if code := run([]string{"-spec", testSpec, "-out", out}, &stderr); code != 0 {
+		t.Fatalf("generate: exit code %d: %s", code, &stderr)
+	}
+	if code, msg := check(); code != 0 {
+		t.Fatalf("check after generating: exit code %d: %s", code, msg)
+	}
+
+	// A hand-written file next to the generated ones is left alone
+	handWritten := filepath.Join(out, "tools", "teams", "helpers.go")
+	os.WriteFile(handWritten, []byte("package tools\n"), 0o644)
+	if code, msg := check(); code != 0 {
+		t.Errorf("check with a hand-written file: exit code %d: %s", code, msg)
+	}
+
+	tool := filepath.Join(out, "tools", "teams", "teamsgetmemberships.go")
+	src, _ := os.ReadFile(tool)
+	os.WriteFile(tool, append(src, "// edited\n"...), 0o644)
+	if code, msg := check(); code != 1 || !strings.Contains(msg, filepath.Join("tools", "teams", "teamsgetmemberships.go")+" is out of date") {
+		t.Errorf("check after an edit: exit code %d: %s", code, msg)
+	}
+	os.WriteFile(tool, src, 0o644)
+
+	stale := filepath.Join(out, "tools", "teams", "teamsdeletemembership.go")
+	os.WriteFile(stale, []byte(header+"package tools\n"), 0o644)
+	if code, msg := check(); code != 1 || !strings.Contains(msg, filepath.Join("tools", "teams", "teamsdeletemembership.go")+" is no longer generated") {
+		t.Errorf("check with a stale file: exit code %d: %s", code, msg)
+	}
+
+	// Generating again brings the tree up to date
+	if code := run([]string{"-spec", testSpec, "-out", out}, &stderr); code != 0 {
+		t.Fatalf("regenerate: exit code %d: %s", code, &stderr)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale file not removed: %v", err)
+	}
+	if _, err := os.Stat(handWritten); err != nil {
+		t.Errorf("hand-written file removed: %v", err)
+	}
+	if code, msg := check(); code != 0 {
+		t.Errorf("check after regenerating: exit code %d: %s", code, msg)
+	}
+}
@@ -0,0 +1,131 @@
+// Command mcpgen generates the MCP tools, models and registry of this server
+// from the Appwrite OpenAPI specification.
+//
+//	go run ./cmd/mcpgen -spec ../../openapi.yaml
+//	go run ./cmd/mcpgen -spec ../../openapi.yaml -check
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/appwrite/mcp-server/openapi"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run generates the files, or with -check reports the ones that are out of
+// date, and returns the exit code.
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mcpgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	specPath := flags.String("spec", "../../openapi.yaml", "OpenAPI specification to generate from")
+	out := flags.String("out", ".", "module directory to write into")
+	check := flags.Bool("check", false, "report files that are out of date instead of writing them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, err := openapi.Load(*specPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpgen: %v\n", err)
+		return 1
+	}
+	g := &generator{spec: spec, out: *out}
+	if err := g.run(); err != nil {
+		fmt.Fprintf(stderr, "mcpgen: %v\n", err)
+		return 1
+	}
+	for _, msg := range g.skipped {
+		fmt.Fprintf(stderr, "mcpgen: skipped %s\n", msg)
+	}
+
+	changed, stale, err := diff(*out, g.files)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpgen: %v\n", err)
+		return 1
+	}
+	if *check {
+		for _, path := range changed {
+			fmt.Fprintf(stderr, "mcpgen: %s is out of date\n", path)
+		}
+		for _, path := range stale {
+			fmt.Fprintf(stderr, "mcpgen: %s is no longer generated\n", path)
+		}
+		if len(changed)+len(stale) > 0 {
+			fmt.Fprintln(stderr, "mcpgen: run go run ./cmd/mcpgen to regenerate")
+			return 1
+		}
+		return 0
+	}
+
+	for _, path := range changed {
+		full := filepath.Join(*out, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			fmt.Fprintf(stderr, "mcpgen: %v\n", err)
+			return 1
+		}
+		if err := os.WriteFile(full, g.files[path], 0o644); err != nil {
+			fmt.Fprintf(stderr, "mcpgen: %v\n", err)
+			return 1
+		}
+	}
+	for _, path := range stale {
+		if err := os.Remove(filepath.Join(*out, path)); err != nil {
+			fmt.Fprintf(stderr, "mcpgen: %v\n", err)
+			return 1
+		}
+	}
+	fmt.Fprintf(stderr, "mcpgen: %d files generated, %d updated, %d removed\n", len(g.files), len(changed), len(stale))
+	return 0
+}
+
+// diff compares the generated files with the ones on disk. It returns the
+// files that are missing or differ, and previously generated files that the
+// spec no longer produces.
+func diff(out string, files map[string][]byte) (changed, stale []string, err error) {
+	for path, src := range files {
+		existing, err := os.ReadFile(filepath.Join(out, path))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, nil, err
+		}
+		if !bytes.Equal(existing, src) {
+			changed = append(changed, path)
+		}
+	}
+	err = filepath.WalkDir(filepath.Join(out, "tools"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		rel, err := filepath.Rel(out, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := files[rel]; ok {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(src, []byte(header)) {
+			stale = append(stale, rel)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	sort.Strings(changed)
+	sort.Strings(stale)
+	return changed, stale, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import "text/template"
+
+const header = "// Code generated by mcpgen from openapi.yaml. DO NOT EDIT.\n\n"
+
+var funcs = template.FuncMap{
+	"quote": quote,
+	"deref": func(b *bool) bool { return *b },
+}
+
+var toolTemplate = template.Must(template.New("tool").Funcs(funcs).Parse(header + `package tools
+
+import (
+	"context"
+{{- if .PathParams}}
+	"fmt"
+	"net/url"
+{{- end}}
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func {{.Name}}Handler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+{{- if .NeedArgs}}
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+{{- end}}
+{{- range .PathParams}}
+		{{.}}Val, ok := args[{{quote .}}]
+		if !ok {
+			return mcp.NewToolResultError({{quote (print "Missing required path parameter: " .)}}), nil
+		}
+		{{.}}, ok := {{.}}Val.(string)
+		if !ok {
+			return mcp.NewToolResultError({{quote (print "Invalid path parameter: " .)}}), nil
+		}
+{{- end}}
+{{- if .QueryParams}}
+		query := appwrite.QueryFromArgs(args{{range .QueryParams}}, {{quote .}}{{end}})
+{{- end}}
+{{- if .Binary}}
+		return appwrite.CallBinary(ctx, cfg, &appwrite.Request{
+{{- else}}
+		var result {{.Result}}
+		return appwrite.Call(ctx, cfg, &appwrite.Request{
+{{- end}}
+			Method: {{quote .Method}},
+			Path: {{.Path}},
+{{- if .QueryParams}}
+			Query: query,
+{{- end}}
+{{- if .HasBody}}
+			JSON: args,
+{{- end}}
+			Security: []string{ {{- range $i, $s := .Security}}{{if $i}}, {{end}}{{quote $s}}{{end -}} },
+			Scope: {{quote .Scope}},
+{{- if .Binary}}
+		})
+{{- else}}
+		}, &result)
+{{- end}}
+	}
+}
+
+func Create{{.Name}}Tool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool({{quote .ToolName}},
+		mcp.WithDescription({{quote .Description}}),
+{{- with .Annotations}}
+		mcp.WithTitleAnnotation({{quote .Title}}),
+		mcp.WithReadOnlyHintAnnotation({{deref .ReadOnlyHint}}),
+		mcp.WithDestructiveHintAnnotation({{deref .DestructiveHint}}),
+		mcp.WithIdempotentHintAnnotation({{deref .IdempotentHint}}),
+		mcp.WithOpenWorldHintAnnotation({{deref .OpenWorldHint}}),
+{{- end}}
+{{- range .Params}}
+		{{.}},
+{{- end}}
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Service:    {{quote .Service}},
+		Handler:    {{.Name}}Handler(cfg),
+	}
+}
+`))
+
+var modelsTemplate = template.Must(template.New("models").Funcs(funcs).Parse(header + `package models
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type Tool struct {
+	Definition mcp.Tool
+	Service    string // Appwrite service the tool belongs to, from the operation tag
+	Handler    func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+{{range .}}
+// {{.Name}} represents the {{.Name}} schema from the OpenAPI specification
+{{- if .Map}}
+type {{.Name}} map[string]interface{}
+{{- else}}
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSON}}"` + "`" + `{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+{{- end}}
+{{end}}`))
+
+var registryTemplate = template.Must(template.New("registry").Funcs(funcs).Parse(header + `package main
+
+import (
+{{- if .Binary}}
+	"github.com/appwrite/mcp-server/appwrite"
+{{- end}}
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+{{- range .Services}}
+	tools_{{.}} "github.com/appwrite/mcp-server/tools/{{.}}"
+{{- end}}
+)
+
+func GetAll(cfg *config.APIConfig) []models.Tool {
+	return []models.Tool{
+{{- range .Tools}}
+		tools_{{.Service}}.Create{{.Name}}Tool(cfg),
+{{- if .Binary}}
+		appwrite.SaveTool(tools_{{.Service}}.Create{{.Name}}Tool(cfg)),
+{{- end}}
+{{- end}}
+	}
+}
+`))
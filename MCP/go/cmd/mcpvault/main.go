@@ -0,0 +1,80 @@
+// Command mcpvault manages the encrypted credential vault read from
+// VAULT_FILE. Encryption and decryption use the key in VAULT_KEY.
+//
+//	go run ./cmd/mcpvault genkey
+//	go run ./cmd/mcpvault check vault.yaml
+//	go run ./cmd/mcpvault encrypt vault.yaml vault.enc
+//	go run ./cmd/mcpvault decrypt vault.enc vault.yaml
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/appwrite/mcp-server/vault"
+)
+
+const usage = `usage:
+  mcpvault genkey               print a new VAULT_KEY
+  mcpvault check <file>         validate a plain or encrypted vault
+  mcpvault encrypt <in> <out>   encrypt a plain vault with VAULT_KEY
+  mcpvault decrypt <in> <out>   decrypt a vault with VAULT_KEY`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "mcpvault: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", usage)
+	}
+	key := os.Getenv("VAULT_KEY")
+	switch cmd := args[0]; {
+	case cmd == "genkey" && len(args) == 1:
+		k, err := vault.NewKey()
+		if err != nil {
+			return err
+		}
+		fmt.Println(k)
+		return nil
+	case cmd == "check" && len(args) == 2:
+		v, err := vault.Load(args[1], key)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d clients, %d profiles\n", args[1], len(v.Clients), len(v.Profiles))
+		return nil
+	case cmd == "encrypt" && len(args) == 3:
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		if vault.Encrypted(data) {
+			return fmt.Errorf("%s is already encrypted", args[1])
+		}
+		// Refuse to seal a vault the server would not load
+		if _, err := vault.Parse(data); err != nil {
+			return fmt.Errorf("%s: %v", args[1], err)
+		}
+		sealed, err := vault.Encrypt(data, key)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(args[2], sealed, 0o600)
+	case cmd == "decrypt" && len(args) == 3:
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		plain, err := vault.Decrypt(data, key)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(args[2], plain, 0o600)
+	default:
+		return fmt.Errorf("unknown command or wrong arguments: %v\n%s", args, usage)
+	}
+}
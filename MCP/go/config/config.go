@@ -1,16 +1,45 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
+
+	"github.com/appwrite/mcp-server/egress"
 )
 
 type APIConfig struct {
 	BaseURL     string
-	BearerToken string // For OAuth2/Bearer authentication
-	APIKey      string // For API key authentication
-	BasicAuth   string // For basic authentication
-	Port        string // For server port configuration
+	BearerToken string       // For OAuth2/Bearer authentication
+	Project     string       // Appwrite project ID, sent as X-Appwrite-Project
+	Key         string       // Appwrite server API key, sent as X-Appwrite-Key
+	JWT         string       // Appwrite account JWT, sent as X-Appwrite-JWT
+	Locale      string       // Preferred response locale, sent as X-Appwrite-Locale
+	BasicAuth   string       // For basic authentication
+	Port        string       // For server port configuration
+	FileRoot    string       // Directory local files may be read from or written to
+	Untrusted   bool         // BaseURL came from a client, so requests must pass the egress policy
+	TLS         *tls.Config  // TLS settings for upstream requests, nil for the defaults
+	SpecFile    string       // OpenAPI document to build tools from instead of the built-in ones
+	Tools       []ToolFilter // Filters a tool must pass to be exposed, e.g. from the environment and a request header
+
+	SessionIdleTimeout time.Duration // How long an unused HTTP session is kept, 0 keeps sessions until deleted
+	AuthTokensFile     string        // Tokens clients must present on the MCP endpoint, unset leaves it open
+	AuthResourceURL    string        // Public URL of the MCP endpoint, published in the OAuth resource metadata
+	AuthServers        []string      // OAuth authorization servers published in the resource metadata
+	VaultFile          string        // Credential vault mapping client tokens to Appwrite profiles
+	WatchInterval      time.Duration // How often the config files are checked for changes, 0 disables watching
+	MetricsPort        string        // Port of a separate listener for /metrics, required for metrics in STDIO mode
+	AuditLog           string        // JSON Lines file every tool call is appended to, unset disables auditing
+	AuditMaxBytes      int64         // Size at which the audit log is rotated, 0 disables rotation
+	AuditMaxFiles      int           // Rotated audit logs kept besides the current one
+	ReadRate           Rate          // Budget of each client for read-only tools
+	WriteRate          Rate          // Budget of each client for all other tools
+
+	Profile  string              // Profile from CONFIG_FILE the upstream settings above come from
+	Profiles map[string]*Profile // Profiles from CONFIG_FILE, selectable with the profile tool argument
 }
 
 func LoadAPIConfig() (*APIConfig, error) {
@@ -19,30 +48,220 @@ func LoadAPIConfig() (*APIConfig, error) {
 	if port == "" {
 		port = os.Getenv("port")
 	}
-	
-	baseURL := os.Getenv("API_BASE_URL")
-	
+
+	// Named profiles from CONFIG_FILE; the default one provides the upstream
+	// settings, with environment variables taking precedence
+	var file *File
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		var err error
+		if file, err = LoadFile(path, os.Getenv); err != nil {
+			return nil, err
+		}
+	}
+	upstream := &Profile{
+		BaseURL: os.Getenv("API_BASE_URL"),
+		Project: os.Getenv("APPWRITE_PROJECT"),
+		Key:     firstEnv("APPWRITE_KEY", "API_KEY"),
+		JWT:     os.Getenv("APPWRITE_JWT"),
+		Locale:  os.Getenv("APPWRITE_LOCALE"),
+	}
+	if file != nil && file.DefaultProfile != "" {
+		upstream = file.Profiles[file.DefaultProfile]
+	}
+	baseURL := upstream.BaseURL
+
 	// Check transport environment variable (both uppercase and lowercase)
 	transport := os.Getenv("TRANSPORT")
 	if transport == "" {
 		transport = os.Getenv("transport")
 	}
-	
-	// For STDIO mode (transport is not "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL is required from environment
-	if transport != "http" && transport != "HTTP" && transport != "https" && transport != "HTTPS" && baseURL == "" {
+
+	// For STDIO mode (transport is not "http"/"HTTP"/"https"/"HTTPS"/"sse"/"SSE"), API_BASE_URL is required from environment or a default profile
+	if transport != "http" && transport != "HTTP" && transport != "https" && transport != "HTTPS" && transport != "sse" && transport != "SSE" && baseURL == "" {
 		return nil, fmt.Errorf("API_BASE_URL environment variable not set")
 	}
-	
-	// For HTTP/HTTPS mode (transport is "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL comes from headers
+
+	// For HTTP/HTTPS/SSE mode, API_BASE_URL comes from headers
 	// so we don't require it from environment variables
 
-	return &APIConfig{
+	tools, err := LoadToolFilter(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionIdleTimeout := 30 * time.Minute
+	if val := os.Getenv("SESSION_IDLE_TIMEOUT"); val != "" {
+		sessionIdleTimeout, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SESSION_IDLE_TIMEOUT: %v", err)
+		}
+	}
+
+	var watchInterval time.Duration
+	if val := os.Getenv("CONFIG_WATCH_INTERVAL"); val != "" {
+		watchInterval, err = time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CONFIG_WATCH_INTERVAL: %v", err)
+		}
+	}
+
+	auditMaxBytes, auditMaxFiles := int64(100<<20), 10
+	if err := envInt("AUDIT_LOG_MAX_BYTES", &auditMaxBytes); err != nil {
+		return nil, err
+	}
+	if err := envInt("AUDIT_LOG_MAX_FILES", &auditMaxFiles); err != nil {
+		return nil, err
+	}
+
+	readRate, err := ParseRate(os.Getenv("RATE_LIMIT_READS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_READS: %v", err)
+	}
+	writeRate, err := ParseRate(os.Getenv("RATE_LIMIT_WRITES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_WRITES: %v", err)
+	}
+
+	cfg := &APIConfig{
 		BaseURL:     baseURL,
 		BearerToken: os.Getenv("BEARER_TOKEN"),
-		APIKey:      os.Getenv("API_KEY"),
+		Project:     upstream.Project,
+		Key:         upstream.Key,
+		JWT:         upstream.JWT,
+		Locale:      upstream.Locale,
+		TLS:         upstream.tlsConfig,
 		BasicAuth:   os.Getenv("BASIC_AUTH"),
 		Port:        port,
-	}, nil
+		FileRoot:    os.Getenv("FILE_ROOT"),
+		SpecFile:    os.Getenv("SPEC_FILE"),
+		Tools:       []ToolFilter{tools},
+
+		SessionIdleTimeout: sessionIdleTimeout,
+		AuthTokensFile:     os.Getenv("AUTH_TOKENS_FILE"),
+		AuthResourceURL:    os.Getenv("AUTH_RESOURCE_URL"),
+		AuthServers:        splitList(os.Getenv("AUTH_AUTHORIZATION_SERVERS")),
+		VaultFile:          os.Getenv("VAULT_FILE"),
+		WatchInterval:      watchInterval,
+		MetricsPort:        os.Getenv("METRICS_PORT"),
+		AuditLog:           os.Getenv("AUDIT_LOG"),
+		AuditMaxBytes:      auditMaxBytes,
+		AuditMaxFiles:      auditMaxFiles,
+		ReadRate:           readRate,
+		WriteRate:          writeRate,
+	}
+	if file != nil {
+		cfg.Profile = file.DefaultProfile
+		cfg.Profiles = file.Profiles
+		cfg.Tools = append(cfg.Tools, file.Tools)
+	}
+	return cfg, nil
+}
+
+// firstEnv returns the first non-empty value among the given variables, so
+// renamed settings keep working under their old name.
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if val := os.Getenv(name); val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
+// ClientConfig controls the HTTP transport used for upstream Appwrite requests.
+type ClientConfig struct {
+	Timeout               time.Duration  // Overall limit for a single request, including reading the body
+	DialTimeout           time.Duration  // Limit for establishing a TCP connection
+	TLSHandshakeTimeout   time.Duration  // Limit for the TLS handshake
+	ResponseHeaderTimeout time.Duration  // Limit for waiting on response headers once the request is sent
+	IdleConnTimeout       time.Duration  // How long idle keep-alive connections are kept around
+	MaxIdleConns          int            // Maximum number of idle keep-alive connections
+	MaxBinaryBytes        int64          // Largest image or file returned inline in a tool result
+	RetryMax              int            // Retries for idempotent requests after a transient failure, 0 disables retries
+	RetryBaseDelay        time.Duration  // Backoff before the first retry, doubled for every further attempt
+	RetryMaxDelay         time.Duration  // Upper bound for a single backoff, including Retry-After
+	BreakerThreshold      int            // Consecutive failures that open the circuit for a base URL, 0 disables it
+	BreakerCooldown       time.Duration  // How long an open circuit fails fast before probing again
+	Egress                *egress.Policy // Rules for base URLs supplied by clients
+	MaxConcurrent         int            // Upstream requests in flight at once across all clients, 0 for no limit
+	QueueTimeout          time.Duration  // How long a request waits for a free slot under MaxConcurrent
 }
 
+func LoadClientConfig() (*ClientConfig, error) {
+	cfg := &ClientConfig{
+		Timeout:               60 * time.Second,
+		DialTimeout:           10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          100,
+		MaxBinaryBytes:        5 << 20,
+		RetryMax:              3,
+		RetryBaseDelay:        200 * time.Millisecond,
+		RetryMaxDelay:         10 * time.Second,
+		BreakerThreshold:      5,
+		BreakerCooldown:       30 * time.Second,
+		QueueTimeout:          10 * time.Second,
+	}
+	durations := []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"HTTP_TIMEOUT", &cfg.Timeout},
+		{"HTTP_DIAL_TIMEOUT", &cfg.DialTimeout},
+		{"HTTP_TLS_HANDSHAKE_TIMEOUT", &cfg.TLSHandshakeTimeout},
+		{"HTTP_RESPONSE_HEADER_TIMEOUT", &cfg.ResponseHeaderTimeout},
+		{"HTTP_IDLE_CONN_TIMEOUT", &cfg.IdleConnTimeout},
+		{"RETRY_BASE_DELAY", &cfg.RetryBaseDelay},
+		{"RETRY_MAX_DELAY", &cfg.RetryMaxDelay},
+		{"BREAKER_COOLDOWN", &cfg.BreakerCooldown},
+		{"UPSTREAM_QUEUE_TIMEOUT", &cfg.QueueTimeout},
+	}
+	for _, d := range durations {
+		val := os.Getenv(d.env)
+		if val == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", d.env, err)
+		}
+		*d.dst = parsed
+	}
+	for _, i := range []struct {
+		env string
+		dst *int
+	}{
+		{"HTTP_MAX_IDLE_CONNS", &cfg.MaxIdleConns},
+		{"RETRY_MAX", &cfg.RetryMax},
+		{"BREAKER_THRESHOLD", &cfg.BreakerThreshold},
+		{"UPSTREAM_MAX_CONCURRENCY", &cfg.MaxConcurrent},
+	} {
+		if err := envInt(i.env, i.dst); err != nil {
+			return nil, err
+		}
+	}
+	if err := envInt("MAX_BINARY_BYTES", &cfg.MaxBinaryBytes); err != nil {
+		return nil, err
+	}
+	policy, err := egress.LoadPolicy(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Egress = policy
+	return cfg, nil
+}
 
+// envInt overwrites dst with the integer in the named variable, if it is set.
+func envInt[T int | int64](name string, dst *T) error {
+	val := os.Getenv(name)
+	if val == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	*dst = T(parsed)
+	return nil
+}
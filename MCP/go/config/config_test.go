@@ -0,0 +1,40 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuditSettings(t *testing.T) {
+	t.Setenv("API_BASE_URL", "https://cloud.appwrite.io/v1")
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("AUDIT_LOG", "/var/log/mcp/audit.jsonl")
+	t.Setenv("AUDIT_LOG_MAX_BYTES", "")
+	t.Setenv("AUDIT_LOG_MAX_FILES", "")
+
+	cfg, err := LoadAPIConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AuditLog != "/var/log/mcp/audit.jsonl" || cfg.AuditMaxBytes != 100<<20 || cfg.AuditMaxFiles != 10 {
+		t.Errorf("defaults: %q, %d bytes, %d files", cfg.AuditLog, cfg.AuditMaxBytes, cfg.AuditMaxFiles)
+	}
+
+	t.Setenv("AUDIT_LOG_MAX_BYTES", "1048576")
+	t.Setenv("AUDIT_LOG_MAX_FILES", "3")
+	if cfg, err = LoadAPIConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AuditMaxBytes != 1<<20 || cfg.AuditMaxFiles != 3 {
+		t.Errorf("overrides: %d bytes, %d files", cfg.AuditMaxBytes, cfg.AuditMaxFiles)
+	}
+
+	for _, name := range []string{"AUDIT_LOG_MAX_BYTES", "AUDIT_LOG_MAX_FILES"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, "10MB")
+			if _, err := LoadAPIConfig(); err == nil || !strings.Contains(err.Error(), "invalid "+name) {
+				t.Errorf("error = %v, want one naming %s", err, name)
+			}
+		})
+	}
+}
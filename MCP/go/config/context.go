@@ -0,0 +1,21 @@
+package config
+
+import "context"
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying cfg as the upstream config for
+// the request or session being served.
+func WithContext(ctx context.Context, cfg *APIConfig) context.Context {
+	return context.WithValue(ctx, contextKey{}, cfg)
+}
+
+// FromContext returns the config stored in ctx by WithContext, or fallback
+// if there is none. Tool handlers are built once with the server's config;
+// requests carry their own through the context.
+func FromContext(ctx context.Context, fallback *APIConfig) *APIConfig {
+	if cfg, ok := ctx.Value(contextKey{}).(*APIConfig); ok && cfg != nil {
+		return cfg
+	}
+	return fallback
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the configuration file named by CONFIG_FILE. It holds named
+// profiles so one server can work with several Appwrite projects.
+type File struct {
+	DefaultProfile string              `yaml:"defaultProfile" json:"defaultProfile"` // Profile used when a tool call names none
+	Profiles       map[string]*Profile `yaml:"profiles" json:"profiles"`
+	Tools          ToolFilter          `yaml:"tools" json:"tools"` // Tools the server exposes, on top of the environment filter
+}
+
+// profileEnv lists the profile fields that environment variables override.
+var profileEnv = []struct {
+	suffix string
+	field  func(*Profile) *string
+}{
+	{"BASE_URL", func(p *Profile) *string { return &p.BaseURL }},
+	{"PROJECT", func(p *Profile) *string { return &p.Project }},
+	{"KEY", func(p *Profile) *string { return &p.Key }},
+	{"JWT", func(p *Profile) *string { return &p.JWT }},
+	{"LOCALE", func(p *Profile) *string { return &p.Locale }},
+}
+
+// LoadFile reads the YAML or JSON configuration file at path, applies
+// environment overrides through get and validates the result. Unknown
+// fields are errors, and errors name the offending field.
+//
+// PROFILE_<NAME>_<FIELD> overrides a field of one profile, e.g.
+// PROFILE_PRODUCTION_KEY, so secrets can stay out of the file. PROFILE
+// selects the default profile, whose fields the flat variables such as
+// API_BASE_URL and APPWRITE_KEY override in turn.
+func LoadFile(path string, get func(string) string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := f.apply(get); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &f, nil
+}
+
+func (f *File) apply(get func(string) string) error {
+	if err := f.Tools.Validate("tools"); err != nil {
+		return err
+	}
+	for _, name := range f.Names() {
+		if f.Profiles[name] == nil {
+			return fmt.Errorf("profiles.%s: empty profile", name)
+		}
+	}
+	if name := get("PROFILE"); name != "" {
+		if f.Profiles[name] == nil {
+			return fmt.Errorf("PROFILE: unknown profile %q", name)
+		}
+		f.DefaultProfile = name
+	}
+	if f.DefaultProfile == "" && len(f.Profiles) == 1 {
+		for name := range f.Profiles {
+			f.DefaultProfile = name
+		}
+	}
+	if f.DefaultProfile != "" && f.Profiles[f.DefaultProfile] == nil {
+		return fmt.Errorf("defaultProfile: unknown profile %q", f.DefaultProfile)
+	}
+
+	for _, name := range f.Names() {
+		p := f.Profiles[name]
+		for _, env := range profileEnv {
+			if val := get("PROFILE_" + envName(name) + "_" + env.suffix); val != "" {
+				*env.field(p) = val
+			}
+		}
+	}
+	if p := f.Profiles[f.DefaultProfile]; p != nil {
+		overrides := map[string]string{
+			"BASE_URL": get("API_BASE_URL"),
+			"PROJECT":  get("APPWRITE_PROJECT"),
+			"KEY":      firstNonEmpty(get("APPWRITE_KEY"), get("API_KEY")),
+			"JWT":      get("APPWRITE_JWT"),
+			"LOCALE":   get("APPWRITE_LOCALE"),
+		}
+		for _, env := range profileEnv {
+			if val := overrides[env.suffix]; val != "" {
+				*env.field(p) = val
+			}
+		}
+	}
+
+	for _, name := range f.Names() {
+		if err := f.Profiles[name].Validate("profiles." + name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Names returns the profile names in sorted order.
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// envName turns a profile name into the form used in variable names:
+// upper case, with anything but letters and digits replaced by _.
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, val := range vals {
+		if val != "" {
+			return val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envFunc(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+const twoProfiles = `
+defaultProfile: prod
+profiles:
+  prod:
+    baseURL: https://prod.example.com/v1
+    project: prod-project
+    key: file-prod-key
+  my-staging:
+    baseURL: https://staging.example.com/v1
+    project: staging-project
+    key: file-staging-key
+`
+
+func TestLoadFileEnvOverrides(t *testing.T) {
+	f, err := LoadFile(writeFile(t, twoProfiles), envFunc(map[string]string{
+		"PROFILE_PROD_KEY":          "env-prod-key",
+		"PROFILE_MY_STAGING_KEY":    "env-staging-key",
+		"PROFILE_MY_STAGING_LOCALE": "de",
+		"API_BASE_URL":              "https://flat.example.com/v1",
+		"APPWRITE_KEY":              "flat-key",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod, staging := f.Profiles["prod"], f.Profiles["my-staging"]
+	// The flat variables override the default profile, even over its own
+	// PROFILE_<NAME>_<FIELD> variables
+	if prod.BaseURL != "https://flat.example.com/v1" || prod.Key != "flat-key" || prod.Project != "prod-project" {
+		t.Errorf("prod = %+v", prod)
+	}
+	if staging.BaseURL != "https://staging.example.com/v1" || staging.Key != "env-staging-key" || staging.Locale != "de" {
+		t.Errorf("staging = %+v", staging)
+	}
+
+	f, err = LoadFile(writeFile(t, twoProfiles), envFunc(map[string]string{"API_KEY": "api-key", "PROFILE_PROD_KEY": "env-prod-key"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := f.Profiles["prod"].Key; key != "api-key" {
+		t.Errorf("API_KEY: key = %q", key)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		want    string
+	}{
+		{"unknown top-level field", "profile: prod\n", nil, "field profile not found"},
+		{"unknown profile field", "profiles:\n  prod:\n    baseURL: https://prod.example.com/v1\n    project: p\n    apiKey: k\n", nil, "field apiKey not found"},
+		{"unknown tls field", "profiles:\n  prod:\n    baseURL: https://prod.example.com/v1\n    project: p\n    tls: {ca: ca.pem}\n", nil, "field ca not found"},
+		{"missing project", "profiles:\n  prod:\n    baseURL: https://prod.example.com/v1\n", nil, "profiles.prod.project: required"},
+		{"missing base URL", "profiles:\n  prod:\n    project: p\n", nil, "profiles.prod.baseURL: required"},
+		{"empty profile", "profiles:\n  prod:\n", nil, "profiles.prod: empty profile"},
+		{"invalid tool pattern", "tools:\n  deny: ['get_[']\n", nil, "tools.deny"},
+		{"invalid profile tool pattern", "profiles:\n  prod:\n    baseURL: https://prod.example.com/v1\n    project: p\n    tools: {allow: ['[']}\n", nil, "profiles.prod.tools.allow"},
+		{"unknown PROFILE", twoProfiles, map[string]string{"PROFILE": "dev"}, `PROFILE: unknown profile "dev"`},
+		{"unknown defaultProfile", strings.Replace(twoProfiles, "defaultProfile: prod", "defaultProfile: dev", 1), nil, `defaultProfile: unknown profile "dev"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, tt.content)
+			_, err := LoadFile(path, envFunc(tt.env))
+			if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.HasPrefix(err.Error(), path+": ") {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileDefaultProfile(t *testing.T) {
+	single := "profiles:\n  only:\n    baseURL: https://only.example.com/v1\n    project: p\n"
+	noDefault := strings.Replace(twoProfiles, "defaultProfile: prod\n", "", 1)
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		want    string
+	}{
+		{"defaultProfile", twoProfiles, nil, "prod"},
+		{"PROFILE overrides defaultProfile", twoProfiles, map[string]string{"PROFILE": "my-staging"}, "my-staging"},
+		{"single profile", single, nil, "only"},
+		{"several profiles and no default", noDefault, nil, ""},
+		{"no profiles", "tools: {readOnly: true}\n", nil, ""},
+		{"empty file", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := LoadFile(writeFile(t, tt.content), envFunc(tt.env))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.DefaultProfile != tt.want {
+				t.Errorf("default profile = %q, want %q", f.DefaultProfile, tt.want)
+			}
+		})
+	}
+
+	// The flat variables only apply to the selected default profile
+	f, err := LoadFile(writeFile(t, twoProfiles), envFunc(map[string]string{"PROFILE": "my-staging", "APPWRITE_PROJECT": "flat-project"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Profiles["my-staging"].Project != "flat-project" || f.Profiles["prod"].Project != "prod-project" {
+		t.Errorf("projects = %q, %q", f.Profiles["my-staging"].Project, f.Profiles["prod"].Project)
+	}
+}
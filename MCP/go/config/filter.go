@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ToolFilter selects the tools a server exposes. Empty lists impose no
+// restriction; Deny wins over Allow.
+type ToolFilter struct {
+	ReadOnly bool     `yaml:"readOnly" json:"readOnly"` // Only expose read-only (GET) tools
+	Allow    []string `yaml:"allow" json:"allow"`       // Tool names or globs to expose
+	Deny     []string `yaml:"deny" json:"deny"`         // Tool names or globs to hide
+	Services []string `yaml:"services" json:"services"` // Services to expose, e.g. users or database
+}
+
+// LoadToolFilter reads READ_ONLY, TOOLS_ALLOW, TOOLS_DENY and TOOLS_SERVICES
+// through get, which is os.Getenv for the environment and Header.Get for
+// per-request settings. Lists are comma-separated.
+func LoadToolFilter(get func(string) string) (ToolFilter, error) {
+	var f ToolFilter
+	if val := get("READ_ONLY"); val != "" {
+		readOnly, err := strconv.ParseBool(val)
+		if err != nil {
+			return f, fmt.Errorf("invalid READ_ONLY: %v", err)
+		}
+		f.ReadOnly = readOnly
+	}
+	f.Allow = splitList(get("TOOLS_ALLOW"))
+	f.Deny = splitList(get("TOOLS_DENY"))
+	f.Services = splitList(get("TOOLS_SERVICES"))
+	if err := f.validate("TOOLS_ALLOW", "TOOLS_DENY"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// validate checks the glob patterns, naming the offending setting.
+func (f ToolFilter) validate(allowName, denyName string) error {
+	for _, list := range []struct {
+		name     string
+		patterns []string
+	}{{allowName, f.Allow}, {denyName, f.Deny}} {
+		for _, pattern := range list.patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid %s pattern %q: %v", list.name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the glob patterns of a filter read from a file; prefix
+// names the filter in errors.
+func (f ToolFilter) Validate(prefix string) error {
+	return f.validate(prefix+".allow", prefix+".deny")
+}
+
+// Allows reports whether the tool with the given name, service and
+// read-only hint passes the filter.
+func (f ToolFilter) Allows(name, service string, readOnly bool) bool {
+	if f.ReadOnly && !readOnly {
+		return false
+	}
+	if len(f.Services) > 0 && !contains(f.Services, service) {
+		return false
+	}
+	if matchAny(f.Deny, name) {
+		return false
+	}
+	return len(f.Allow) == 0 || matchAny(f.Allow, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, val string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, val) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToolFilterAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   ToolFilter
+		tool     string
+		service  string
+		readOnly bool
+		want     bool
+	}{
+		{"empty filter", ToolFilter{}, "delete_users_userId", "users", false, true},
+		{"allow exact name", ToolFilter{Allow: []string{"get_users"}}, "get_users", "users", true, true},
+		{"allow glob", ToolFilter{Allow: []string{"get_users*"}}, "get_users_userId", "users", true, true},
+		{"not allowed", ToolFilter{Allow: []string{"get_users*"}}, "post_users", "users", false, false},
+		{"deny glob", ToolFilter{Deny: []string{"delete_*"}}, "delete_users_userId", "users", false, false},
+		{"deny other tool", ToolFilter{Deny: []string{"delete_*"}}, "get_users", "users", true, true},
+		{"deny wins over allow", ToolFilter{Allow: []string{"*_users_*"}, Deny: []string{"delete_*"}}, "delete_users_userId", "users", false, false},
+		{"service listed", ToolFilter{Services: []string{"Users"}}, "get_users", "users", true, true},
+		{"service not listed", ToolFilter{Services: []string{"teams"}}, "get_users", "users", true, false},
+		{"read-only tool", ToolFilter{ReadOnly: true}, "get_users", "users", true, true},
+		{"write tool in read-only mode", ToolFilter{ReadOnly: true}, "post_users", "users", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Allows(tt.tool, tt.service, tt.readOnly); got != tt.want {
+				t.Errorf("Allows(%s) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadToolFilter(t *testing.T) {
+	env := map[string]string{
+		"READ_ONLY":      "true",
+		"TOOLS_ALLOW":    " get_users* , get_teams",
+		"TOOLS_DENY":     "get_users_userId_logs",
+		"TOOLS_SERVICES": "users,teams,",
+	}
+	f, err := LoadToolFilter(func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.ReadOnly || strings.Join(f.Allow, "|") != "get_users*|get_teams" || strings.Join(f.Deny, "|") != "get_users_userId_logs" || strings.Join(f.Services, "|") != "users|teams" {
+		t.Errorf("filter = %+v", f)
+	}
+
+	for name, val := range map[string]string{"READ_ONLY": "maybe", "TOOLS_ALLOW": "get_[users", "TOOLS_DENY": "get_[users"} {
+		_, err := LoadToolFilter(func(n string) string {
+			if n == name {
+				return val
+			}
+			return ""
+		})
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s=%s: error %v does not name the setting", name, val, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// Profile is a named set of upstream settings for one Appwrite project.
+type Profile struct {
+	BaseURL string     `yaml:"baseURL" json:"baseURL"`
+	Project string     `yaml:"project" json:"project"`
+	Key     string     `yaml:"key" json:"key"`
+	JWT     string     `yaml:"jwt" json:"jwt"`
+	Locale  string     `yaml:"locale" json:"locale"`
+	TLS     *TLSConfig `yaml:"tls" json:"tls"`
+	Tools   ToolFilter `yaml:"tools" json:"tools"` // Tools the profile may use
+
+	tlsConfig *tls.Config // Built from TLS by Validate
+}
+
+// TLSConfig customizes TLS for upstream requests, e.g. for a self-hosted
+// Appwrite behind a private certificate authority.
+type TLSConfig struct {
+	CAFile             string `yaml:"caFile" json:"caFile"`                         // PEM certificates trusted in addition to the system roots
+	CertFile           string `yaml:"certFile" json:"certFile"`                     // PEM client certificate, with KeyFile
+	KeyFile            string `yaml:"keyFile" json:"keyFile"`                       // PEM key of the client certificate
+	ServerName         string `yaml:"serverName" json:"serverName"`                 // Name expected in the server certificate, if not the host
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify" json:"insecureSkipVerify"` // Accept any server certificate; for testing only
+}
+
+// Validate checks the profile and loads its TLS files; prefix names the
+// profile in errors, e.g. profiles.production.
+func (p *Profile) Validate(prefix string) error {
+	switch {
+	case p.BaseURL == "":
+		return fmt.Errorf("%s.baseURL: required", prefix)
+	case p.Project == "":
+		return fmt.Errorf("%s.project: required", prefix)
+	}
+	if err := p.Tools.Validate(prefix + ".tools"); err != nil {
+		return err
+	}
+	if p.TLS == nil {
+		p.tlsConfig = nil
+		return nil
+	}
+	tlsConfig, err := p.TLS.build(prefix + ".tls")
+	if err != nil {
+		return err
+	}
+	p.tlsConfig = tlsConfig
+	return nil
+}
+
+func (c *TLSConfig) build(prefix string) (*tls.Config, error) {
+	cfg := &tls.Config{ServerName: c.ServerName, InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s.caFile: %v", prefix, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s.caFile: no PEM certificates in %s", prefix, c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, fmt.Errorf("%s.keyFile: certFile and keyFile must be set together", prefix)
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s.certFile: %v", prefix, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// APIConfig returns the upstream config for the profile. Server-level
+// settings and tool filters are taken from base; the profile's own filter
+// is added to them.
+func (p *Profile) APIConfig(base *APIConfig) *APIConfig {
+	return &APIConfig{
+		BaseURL:  p.BaseURL,
+		Project:  p.Project,
+		Key:      p.Key,
+		JWT:      p.JWT,
+		Locale:   p.Locale,
+		TLS:      p.tlsConfig,
+		FileRoot: base.FileRoot,
+		Tools:    append(append([]ToolFilter{}, base.Tools...), p.Tools),
+	}
+}
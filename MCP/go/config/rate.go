@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate is a token bucket budget: bursts of up to Count calls, refilled at
+// Count calls per Per. The zero Rate imposes no limit.
+type Rate struct {
+	Count int
+	Per   time.Duration
+}
+
+// ParseRate parses a rate such as "60/1m" or "5/s". An empty string is the
+// zero Rate.
+func ParseRate(val string) (Rate, error) {
+	if val == "" {
+		return Rate{}, nil
+	}
+	count, per, ok := strings.Cut(val, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("%q is not of the form <count>/<duration>, e.g. 60/1m", val)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n < 1 {
+		return Rate{}, fmt.Errorf("invalid count in %q", val)
+	}
+	per = strings.TrimSpace(per)
+	// "5/s" means 5 per second
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("invalid duration in %q", val)
+	}
+	return Rate{Count: n, Per: d}, nil
+}
+
+// String formats r like ParseRate accepts it.
+func (r Rate) String() string {
+	if r.Count == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d/%s", r.Count, r.Per)
+}
@@ -0,0 +1,236 @@
+// Package egress decides which upstream URLs the server may contact on behalf
+// of clients. Base URLs supplied by clients must match an allowlist of
+// patterns, and may not reach private, loopback, link-local or cloud metadata
+// addresses unless those networks are explicitly allowed. Addresses are
+// checked after DNS resolution, when the connection is dialed, so DNS
+// rebinding and redirects cannot get around the policy.
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+)
+
+// Policy restricts the upstream URLs taken from clients.
+type Policy struct {
+	Allow        []string     // Base URL patterns such as https://*.appwrite.io/v1, empty allows any public URL
+	AllowNetwork []*net.IPNet // Networks that may be reached despite being private
+}
+
+// Error reports a URL or address the policy does not allow.
+type Error struct {
+	Target string // URL or address that was refused
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("upstream %s is not allowed: %s", e.Target, e.Reason)
+}
+
+// IsBlocked reports whether err, or an error it wraps, comes from the policy.
+func IsBlocked(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
+
+// blockedNetworks are refused in addition to what the net.IP predicates
+// cover: this-network, carrier-grade NAT, IETF protocol assignments,
+// benchmarking, reserved space and NAT64, which can embed any IPv4 address.
+var blockedNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+	"64:ff9b:1::/48",
+)
+
+// LoadPolicy reads the policy from API_BASE_URL_ALLOW, a comma-separated list
+// of base URL patterns, and API_BASE_URL_ALLOW_NETWORKS, a comma-separated
+// list of CIDR networks exempt from the private address check.
+func LoadPolicy(get func(string) string) (*Policy, error) {
+	p := &Policy{}
+	for _, pattern := range splitList(get("API_BASE_URL_ALLOW")) {
+		if _, _, _, err := splitPattern(pattern); err != nil {
+			return nil, fmt.Errorf("invalid API_BASE_URL_ALLOW: %v", err)
+		}
+		p.Allow = append(p.Allow, pattern)
+	}
+	for _, cidr := range splitList(get("API_BASE_URL_ALLOW_NETWORKS")) {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API_BASE_URL_ALLOW_NETWORKS: %v", err)
+		}
+		p.AllowNetwork = append(p.AllowNetwork, network)
+	}
+	return p, nil
+}
+
+// Check validates a client-supplied base URL: it must match the allowlist and
+// every address its host resolves to must be allowed. The dial-time check
+// still applies, since the host may resolve differently later.
+func (p *Policy) Check(ctx context.Context, raw string) error {
+	u, err := p.CheckURL(raw)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return &Error{Target: raw, Reason: "host does not resolve"}
+	}
+	for _, addr := range addrs {
+		if reason := p.refuse(addr.IP); reason != "" {
+			return &Error{Target: raw, Reason: fmt.Sprintf("%s resolves to %s", u.Hostname(), reason)}
+		}
+	}
+	return nil
+}
+
+// CheckURL validates the form of raw and matches it against the allowlist.
+// Hosts given as IP addresses are checked right away.
+func (p *Policy) CheckURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, &Error{Target: raw, Reason: "invalid URL"}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, &Error{Target: raw, Reason: "scheme must be http or https"}
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return nil, &Error{Target: raw, Reason: "URL must have a host and no user info"}
+	}
+	if len(p.Allow) > 0 && !p.matches(u) {
+		return nil, &Error{Target: raw, Reason: "does not match API_BASE_URL_ALLOW"}
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if reason := p.refuse(ip); reason != "" {
+			return nil, &Error{Target: raw, Reason: reason}
+		}
+	}
+	return u, nil
+}
+
+// CheckIP refuses private, loopback, link-local, multicast and reserved
+// addresses outside AllowNetwork. Link-local covers the 169.254.169.254
+// metadata endpoint of the major clouds.
+func (p *Policy) CheckIP(ip net.IP) error {
+	if reason := p.refuse(ip); reason != "" {
+		return &Error{Target: ip.String(), Reason: reason}
+	}
+	return nil
+}
+
+// refuse returns why ip may not be contacted, or "" if it may.
+func (p *Policy) refuse(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, network := range p.AllowNetwork {
+		if network.Contains(ip) {
+			return ""
+		}
+	}
+	blocked := ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+	for _, network := range blockedNetworks {
+		blocked = blocked || network.Contains(ip)
+	}
+	if blocked {
+		return "private or reserved address " + ip.String()
+	}
+	return ""
+}
+
+// Dialer returns a copy of d that checks every address right before
+// connecting, after DNS resolution.
+func (p *Policy) Dialer(d *net.Dialer) *net.Dialer {
+	guarded := *d
+	guarded.Control = func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return &Error{Target: address, Reason: "invalid address"}
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return &Error{Target: address, Reason: "unresolved address"}
+		}
+		return p.CheckIP(ip)
+	}
+	return &guarded
+}
+
+// CheckRedirect is an http.Client CheckRedirect function that holds
+// redirect targets to the same rules as the base URL.
+func (p *Policy) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	_, err := p.CheckURL(req.URL.String())
+	return err
+}
+
+// matches reports whether u is covered by one of the allowlist patterns. A
+// pattern is scheme://host[:port][/path]: the host may use path.Match
+// wildcards, and the URL path must equal the pattern path or lie below it.
+func (p *Policy) matches(u *url.URL) bool {
+	for _, pattern := range p.Allow {
+		scheme, host, prefix, err := splitPattern(pattern)
+		if err != nil || scheme != u.Scheme {
+			continue
+		}
+		if ok, _ := path.Match(host, strings.ToLower(u.Host)); !ok {
+			continue
+		}
+		escaped := strings.TrimRight(u.EscapedPath(), "/")
+		if prefix == "" || escaped == prefix || strings.HasPrefix(escaped, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPattern(pattern string) (scheme, host, prefix string, err error) {
+	scheme, rest, ok := strings.Cut(pattern, "://")
+	if !ok || (scheme != "http" && scheme != "https") {
+		return "", "", "", fmt.Errorf("%q: pattern must start with http:// or https://", pattern)
+	}
+	host, prefix, _ = strings.Cut(rest, "/")
+	if host == "" {
+		return "", "", "", fmt.Errorf("%q: pattern has no host", pattern)
+	}
+	if _, err := path.Match(host, ""); err != nil {
+		return "", "", "", fmt.Errorf("%q: %v", pattern, err)
+	}
+	if prefix = strings.TrimRight(prefix, "/"); prefix != "" {
+		prefix = "/" + prefix
+	}
+	return scheme, strings.ToLower(host), prefix, nil
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, networks[i], _ = net.ParseCIDR(cidr)
+	}
+	return networks
+}
+
+// splitList splits a comma-separated list and drops empty entries.
+func splitList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
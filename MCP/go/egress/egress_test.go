@@ -0,0 +1,207 @@
+package egress
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckURL(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	tests := []struct {
+		name    string
+		policy  Policy
+		url     string
+		allowed bool
+	}{
+		{"public", Policy{}, "https://cloud.appwrite.io/v1", true},
+		{"scheme", Policy{}, "file:///etc/passwd", false},
+		{"user info", Policy{}, "https://user@cloud.appwrite.io/v1", false},
+		{"loopback", Policy{}, "http://127.0.0.1/v1", false},
+		{"loopback IPv6", Policy{}, "http://[::1]/v1", false},
+		{"mapped loopback", Policy{}, "http://[::ffff:127.0.0.1]/v1", false},
+		{"private", Policy{}, "http://10.0.0.5/v1", false},
+		{"unspecified", Policy{}, "http://0.0.0.0/v1", false},
+		{"metadata", Policy{}, "http://169.254.169.254/latest/meta-data", false},
+		{"NAT64 metadata", Policy{}, "http://[64:ff9b::a9fe:a9fe]/", false},
+		{"carrier-grade NAT", Policy{}, "http://100.64.0.1/v1", false},
+		{"allowed network", Policy{AllowNetwork: []*net.IPNet{loopback}}, "http://127.0.0.1/v1", true},
+		{"allowed network only", Policy{AllowNetwork: []*net.IPNet{loopback}}, "http://169.254.169.254/", false},
+		{"pattern", Policy{Allow: []string{"https://*.appwrite.io/v1"}}, "https://cloud.appwrite.io/v1", true},
+		{"pattern subpath", Policy{Allow: []string{"https://*.appwrite.io/v1"}}, "https://cloud.appwrite.io/v1/", true},
+		{"pattern path", Policy{Allow: []string{"https://*.appwrite.io/v1"}}, "https://cloud.appwrite.io/v2", false},
+		{"pattern path prefix", Policy{Allow: []string{"https://*.appwrite.io/v1"}}, "https://cloud.appwrite.io/v10", false},
+		{"pattern scheme", Policy{Allow: []string{"https://*.appwrite.io"}}, "http://cloud.appwrite.io/v1", false},
+		{"pattern suffix", Policy{Allow: []string{"https://*.appwrite.io"}}, "https://cloud.appwrite.io.evil.com/v1", false},
+		{"pattern port", Policy{Allow: []string{"https://*.appwrite.io"}}, "https://cloud.appwrite.io:8443/v1", false},
+		{"pattern case", Policy{Allow: []string{"https://Cloud.Appwrite.io"}}, "https://CLOUD.appwrite.io/v1", true},
+		{"pattern private", Policy{Allow: []string{"http://127.0.0.1"}}, "http://127.0.0.1/v1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.policy.CheckURL(tt.url)
+			if tt.allowed && err != nil {
+				t.Fatalf("CheckURL(%q) = %v, want allowed", tt.url, err)
+			}
+			if !tt.allowed && !IsBlocked(err) {
+				t.Fatalf("CheckURL(%q) = %v, want blocked", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestCheckResolvesHost(t *testing.T) {
+	err := (&Policy{}).Check(context.Background(), "http://localhost:8080/v1")
+	if !IsBlocked(err) {
+		t.Fatalf("Check(localhost) = %v, want blocked", err)
+	}
+	if !strings.Contains(err.Error(), "localhost resolves to private or reserved address") {
+		t.Errorf("error %q does not explain the refusal", err)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	env := map[string]string{
+		"API_BASE_URL_ALLOW":          "https://*.appwrite.io/v1, https://appwrite.example.com",
+		"API_BASE_URL_ALLOW_NETWORKS": "10.0.0.0/8",
+	}
+	p, err := LoadPolicy(func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Allow) != 2 || len(p.AllowNetwork) != 1 {
+		t.Fatalf("LoadPolicy = %+v", p)
+	}
+	for name, val := range map[string]string{
+		"API_BASE_URL_ALLOW":          "cloud.appwrite.io",
+		"API_BASE_URL_ALLOW_NETWORKS": "10.0.0.0",
+	} {
+		if _, err := LoadPolicy(func(n string) string { return map[string]string{name: val}[n] }); err == nil {
+			t.Errorf("LoadPolicy accepted %s=%s", name, val)
+		}
+	}
+}
+
+// upstream starts a server that counts its requests, optionally redirecting
+// them to location.
+func upstream(t *testing.T, location string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	hits := &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if location != "" {
+			http.Redirect(w, r, location, http.StatusFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, hits
+}
+
+// client builds an HTTP client guarded by p the way the Appwrite client is.
+func client(p *Policy) *http.Client {
+	dialer := &net.Dialer{Timeout: time.Second}
+	return &http.Client{
+		Transport:     &http.Transport{DialContext: p.Dialer(dialer).DialContext},
+		CheckRedirect: p.CheckRedirect,
+	}
+}
+
+func TestRedirectBypass(t *testing.T) {
+	internal, internalHits := upstream(t, "")
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+
+	tests := []struct {
+		name     string
+		policy   *Policy
+		location string
+	}{
+		// The first hop matches the allowlist, the second does not
+		{"to host outside allowlist", &Policy{AllowNetwork: []*net.IPNet{loopback}}, internal.URL},
+		{"to metadata address", &Policy{AllowNetwork: []*net.IPNet{loopback}}, "http://169.254.169.254/latest/meta-data/"},
+		{"to mapped IPv6 metadata", &Policy{AllowNetwork: []*net.IPNet{loopback}}, "http://[::ffff:169.254.169.254]/latest/meta-data/"},
+		{"to other scheme", &Policy{AllowNetwork: []*net.IPNet{loopback}}, "ftp://example.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, entryHits := upstream(t, tt.location)
+			tt.policy.Allow = []string{entry.URL}
+			internalHits.Store(0)
+
+			resp, err := client(tt.policy).Get(entry.URL + "/v1/health")
+			if err == nil {
+				resp.Body.Close()
+				t.Fatalf("redirect to %s was followed", tt.location)
+			}
+			if !IsBlocked(err) {
+				t.Fatalf("error %v does not come from the policy", err)
+			}
+			if entryHits.Load() != 1 || internalHits.Load() != 0 {
+				t.Errorf("entry hits = %d, internal hits = %d", entryHits.Load(), internalHits.Load())
+			}
+		})
+	}
+}
+
+func TestRedirectToHostnameResolvingPrivate(t *testing.T) {
+	// The redirect target is a hostname, so it passes the URL checks, but it
+	// resolves to loopback and the dial is refused
+	internal, internalHits := upstream(t, "")
+	_, port, _ := net.SplitHostPort(internal.Listener.Addr().String())
+	entry, entryHits := upstream(t, "http://localhost:"+port+"/")
+
+	// The entry server is dialed directly; the redirect goes through p
+	p := &Policy{}
+	guarded := p.Dialer(&net.Dialer{}).DialContext
+	entryClient := &http.Client{
+		CheckRedirect: p.CheckRedirect,
+		Transport: &http.Transport{DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if addr == entry.Listener.Addr().String() {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			}
+			return guarded(ctx, network, addr)
+		}},
+	}
+
+	resp, err := entryClient.Get(entry.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("redirect to localhost was followed")
+	}
+	if !IsBlocked(err) {
+		t.Fatalf("error %v does not come from the policy", err)
+	}
+	if entryHits.Load() != 1 || internalHits.Load() != 0 {
+		t.Errorf("entry hits = %d, internal hits = %d", entryHits.Load(), internalHits.Load())
+	}
+}
+
+func TestDialRefusesResolvedPrivateAddress(t *testing.T) {
+	// An allowlisted hostname that resolves to a private address, as with
+	// DNS rebinding, is refused when dialing
+	srv, hits := upstream(t, "")
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	p := &Policy{Allow: []string{"http://localhost:*"}}
+
+	target := "http://localhost:" + port + "/v1"
+	if _, err := p.CheckURL(target); err != nil {
+		t.Fatalf("CheckURL(%q) = %v, want allowed", target, err)
+	}
+	resp, err := client(p).Get(target)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("request to localhost was sent")
+	}
+	if !IsBlocked(err) {
+		t.Fatalf("error %v does not come from the policy", err)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("server was hit %d times", hits.Load())
+	}
+}
@@ -0,0 +1,109 @@
+package files
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Package returns a gzipped tarball for path, which must resolve below root.
+// Directories are archived on the fly, .tar.gz/.tgz files are passed through
+// unchanged and plain .tar files are compressed.
+func Package(root, path string) (io.ReadCloser, string, error) {
+	resolved, err := Resolve(root, path)
+	if err != nil {
+		return nil, "", err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, "", err
+	}
+	name := filepath.Base(resolved)
+	lower := strings.ToLower(name)
+
+	switch {
+	case info.IsDir():
+		return archiveDir(resolved), name + ".tar.gz", nil
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		f, err := os.Open(resolved)
+		if err != nil {
+			return nil, "", err
+		}
+		return f, name, nil
+	case strings.HasSuffix(lower, ".tar"):
+		f, err := os.Open(resolved)
+		if err != nil {
+			return nil, "", err
+		}
+		return gzipStream(f), name + ".gz", nil
+	}
+	return nil, "", fmt.Errorf("%q is neither a directory nor a .tar, .tar.gz or .tgz archive", path)
+}
+
+func gzipStream(src io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer src.Close()
+		gw := gzip.NewWriter(pw)
+		if _, err := io.Copy(gw, src); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(gw.Close())
+	}()
+	return pr
+}
+
+func archiveDir(dir string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		gw := gzip.NewWriter(pw)
+		tw := tar.NewWriter(gw)
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil || rel == "." {
+				return err
+			}
+			// Symlinks could point outside of the root, so they are skipped.
+			if info.Mode()&os.ModeSymlink != 0 {
+				return nil
+			}
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		if err == nil {
+			err = gw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
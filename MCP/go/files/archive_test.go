@@ -0,0 +1,165 @@
+package files
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// entries reads a gzipped tarball into a map of entry names to contents.
+func entries(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	got := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return got
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		got[hdr.Name] = string(data)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPackageDirectory(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	writeFiles(t, root, map[string]string{"fn/index.js": "main", "fn/lib/util.js": "util"})
+	writeFiles(t, outside, map[string]string{"secret.txt": "secret"})
+	for link, target := range map[string]string{
+		"fn/secret.txt": filepath.Join(outside, "secret.txt"),
+		"fn/outside":    outside,
+		"fn/lib/main":   "../index.js",
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	body, name, err := Package(root, "fn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if name != "fn.tar.gz" {
+		t.Errorf("name = %q", name)
+	}
+	got := entries(t, body)
+	var names []string
+	for n := range got {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	// Symlinks are left out, wherever they point
+	if strings.Join(names, ",") != "index.js,lib/,lib/util.js" {
+		t.Errorf("entries = %v", names)
+	}
+	if got["index.js"] != "main" || got["lib/util.js"] != "util" {
+		t.Errorf("contents = %v", got)
+	}
+}
+
+func TestPackageArchives(t *testing.T) {
+	root := t.TempDir()
+	var tarball bytes.Buffer
+	tw := tar.NewWriter(&tarball)
+	tw.WriteHeader(&tar.Header{Name: "index.js", Mode: 0o644, Size: 4})
+	tw.Write([]byte("main"))
+	tw.Close()
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write(tarball.Bytes())
+	gw.Close()
+	for name, data := range map[string][]byte{"code.tar": tarball.Bytes(), "code.tar.gz": gzipped.Bytes(), "code.TGZ": gzipped.Bytes()} {
+		if err := os.WriteFile(filepath.Join(root, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Plain tarballs are compressed
+	body, name, err := Package(root, "code.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := entries(t, body); name != "code.tar.gz" || got["index.js"] != "main" || len(got) != 1 {
+		t.Errorf("%s: %v", name, got)
+	}
+	body.Close()
+
+	// Compressed ones are passed through unchanged
+	for _, path := range []string{"code.tar.gz", "code.TGZ"} {
+		body, name, err := Package(root, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(body)
+		body.Close()
+		if name != path || !bytes.Equal(data, gzipped.Bytes()) {
+			t.Errorf("%s: name %q, %d bytes", path, name, len(data))
+		}
+	}
+}
+
+func TestPackageRefusals(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	writeFiles(t, root, map[string]string{"notes.txt": "notes"})
+	writeFiles(t, outside, map[string]string{"fn/index.js": "main", "code.tar.gz": "archive"})
+	for link, target := range map[string]string{
+		"linked-fn":      filepath.Join(outside, "fn"),
+		"linked.tar.gz":  filepath.Join(outside, "code.tar.gz"),
+		"linked-outside": outside,
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name, root, path, want string
+	}{
+		{"dot-dot escape", root, "../" + filepath.Base(outside) + "/fn", "outside of FILE_ROOT"},
+		{"absolute path outside", root, filepath.Join(outside, "fn"), "outside of FILE_ROOT"},
+		{"symlinked directory outside", root, "linked-fn", "outside of FILE_ROOT"},
+		{"symlinked archive outside", root, "linked.tar.gz", "outside of FILE_ROOT"},
+		{"through a symlinked directory", root, "linked-outside/fn", "outside of FILE_ROOT"},
+		{"plain file", root, "notes.txt", "neither a directory nor"},
+		{"missing", root, "missing", "no such file"},
+		{"no FILE_ROOT", "", "fn", "FILE_ROOT"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _, err := Package(tc.root, tc.path)
+			if err == nil {
+				body.Close()
+				t.Fatal("packaged")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
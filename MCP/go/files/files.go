@@ -0,0 +1,140 @@
+package files
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resolve maps a user supplied path onto root and rejects anything that would
+// escape it, including through symlinks. An empty root disables local file
+// access entirely.
+func Resolve(root, path string) (string, error) {
+	if root == "" {
+		return "", fmt.Errorf("local file access is disabled; set FILE_ROOT to allow it")
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	if realRoot, err := filepath.EvalSymlinks(absRoot); err == nil {
+		absRoot = realRoot
+	}
+	target := path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(absRoot, target)
+	}
+	target = filepath.Clean(target)
+	if realTarget, err := filepath.EvalSymlinks(target); err == nil {
+		target = realTarget
+	} else if realDir, err := filepath.EvalSymlinks(filepath.Dir(target)); err == nil {
+		target = filepath.Join(realDir, filepath.Base(target))
+	}
+	rel, err := filepath.Rel(absRoot, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside of FILE_ROOT", path)
+	}
+	return target, nil
+}
+
+// Source describes file content given either inline as base64 or as a path
+// below the configured root.
+type Source struct {
+	Content  string
+	Path     string
+	Filename string
+}
+
+// Open returns a reader for the source content along with the file name to
+// report upstream.
+func (s Source) Open(root string) (io.ReadCloser, string, error) {
+	switch {
+	case s.Content != "" && s.Path != "":
+		return nil, "", fmt.Errorf("pass either content or path, not both")
+	case s.Content != "":
+		data, err := base64.StdEncoding.DecodeString(s.Content)
+		if err != nil {
+			return nil, "", fmt.Errorf("content is not valid base64: %w", err)
+		}
+		name := s.Filename
+		if name == "" {
+			name = "file"
+		}
+		return io.NopCloser(bytes.NewReader(data)), name, nil
+	case s.Path != "":
+		resolved, err := Resolve(root, s.Path)
+		if err != nil {
+			return nil, "", err
+		}
+		f, err := os.Open(resolved)
+		if err != nil {
+			return nil, "", err
+		}
+		name := s.Filename
+		if name == "" {
+			name = filepath.Base(resolved)
+		}
+		return f, name, nil
+	}
+	return nil, "", fmt.Errorf("either content or path is required")
+}
+
+// Part is a single multipart/form-data field. Parts with a Reader are sent as
+// file uploads, everything else as plain values.
+type Part struct {
+	Name     string
+	Value    string
+	Filename string
+	Reader   io.Reader
+}
+
+// Multipart streams parts as a multipart/form-data body without buffering file
+// content in memory. It returns the body reader and its content type.
+func Multipart(parts []Part) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		for _, p := range parts {
+			if p.Reader == nil {
+				if err := mw.WriteField(p.Name, p.Value); err != nil {
+					pw.CloseWithError(err)
+					return
+				}
+				continue
+			}
+			w, err := mw.CreateFormFile(p.Name, p.Filename)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(w, p.Reader); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(mw.Close())
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+// Strings converts an MCP array argument into a string slice.
+func Strings(val any) ([]string, error) {
+	items, ok := val.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an array of strings")
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an array of strings")
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
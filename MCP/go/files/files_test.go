@@ -0,0 +1,77 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	for _, dir := range []string{"sub", "sub/deep"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{filepath.Join(root, "sub/file.txt"), filepath.Join(outside, "secret.txt")} {
+		if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{
+		"escape":     filepath.Join(outside, "secret.txt"),
+		"escapedir":  outside,
+		"inner":      filepath.Join(root, "sub"),
+		"sub/parent": "..",
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name, root, path string
+		want             string // Resolved path below root, "" if refused
+	}{
+		{"relative file", root, "sub/file.txt", "sub/file.txt"},
+		{"new file", root, "new.txt", "new.txt"},
+		{"absolute path inside", root, filepath.Join(root, "sub/file.txt"), "sub/file.txt"},
+		{"dot-dot within root", root, "sub/deep/../file.txt", "sub/file.txt"},
+		{"dot-dot escape", root, "../secret.txt", ""},
+		{"nested dot-dot escape", root, "sub/../../secret.txt", ""},
+		{"dot-dot to root parent", root, "..", ""},
+		{"absolute path outside", root, filepath.Join(outside, "secret.txt"), ""},
+		{"absolute system path", root, "/etc/passwd", ""},
+		{"symlink to outside file", root, "escape", ""},
+		{"file through symlinked dir outside", root, "escapedir/secret.txt", ""},
+		{"new file in symlinked dir outside", root, "escapedir/new.txt", ""},
+		{"new file in symlinked dir inside", root, "inner/new.txt", "sub/new.txt"},
+		{"symlink to root", root, "sub/parent/sub/file.txt", "sub/file.txt"},
+		{"empty root", "", "file.txt", ""},
+		{"empty root with absolute path", "", filepath.Join(root, "sub/file.txt"), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Resolve(tc.root, tc.path)
+			if tc.want == "" {
+				if err == nil {
+					t.Fatalf("Resolve(%q) = %q, want an error", tc.path, got)
+				}
+				if tc.root == "" && !strings.Contains(err.Error(), "FILE_ROOT") {
+					t.Errorf("err = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(root, tc.want); got != want {
+				t.Errorf("Resolve(%q) = %q, want %q", tc.path, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/egress"
+	"github.com/appwrite/mcp-server/metrics"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// endedRetention is how long terminated and expired session IDs are
+// remembered, so clients still using one get 404 and re-initialize.
+const endedRetention = time.Hour
+
+// sessionStore issues streamable HTTP session IDs, binds each session to
+// the client that started it and keeps the upstream config of the session,
+// so requests after initialize may omit the configuration headers. It
+// implements server.SessionIdManager.
+type sessionStore struct {
+	mu       sync.Mutex
+	idle     time.Duration // Sessions unused for this long expire, 0 disables expiry
+	sessions map[string]*session
+	ended    map[string]time.Time
+}
+
+type session struct {
+	cfg      *config.APIConfig
+	owner    string // Label of the client that started the session, "" without authentication
+	bound    bool   // Whether owner is set
+	lastSeen time.Time
+	streams  map[*http.Request]context.CancelFunc // Open GET streams, closed on termination
+}
+
+func newSessionStore(idle time.Duration) *sessionStore {
+	return &sessionStore{
+		idle:     idle,
+		sessions: map[string]*session{},
+		ended:    map[string]time.Time{},
+	}
+}
+
+func (s *sessionStore) Generate() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("generate session ID: %v", err))
+	}
+	id := "mcp-session-" + hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(time.Now())
+	s.sessions[id] = &session{lastSeen: time.Now(), streams: map[*http.Request]context.CancelFunc{}}
+	metrics.SessionStarted()
+	return id
+}
+
+func (s *sessionStore) Validate(id string) (isTerminated bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.validate(id)
+}
+
+func (s *sessionStore) Terminate(id string) (isNotAllowed bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[id]; ok {
+		s.end(id, sess)
+	}
+	return false, nil
+}
+
+// validate must be called with s.mu held.
+func (s *sessionStore) validate(id string) (bool, error) {
+	if _, ok := s.ended[id]; ok {
+		return true, nil
+	}
+	sess, ok := s.sessions[id]
+	if !ok {
+		return false, fmt.Errorf("unknown session %q", id)
+	}
+	now := time.Now()
+	if s.idle > 0 && now.Sub(sess.lastSeen) > s.idle {
+		s.end(id, sess)
+		return true, nil
+	}
+	sess.lastSeen = now
+	return false, nil
+}
+
+// end must be called with s.mu held.
+func (s *sessionStore) end(id string, sess *session) {
+	for _, cancel := range sess.streams {
+		cancel()
+	}
+	delete(s.sessions, id)
+	s.ended[id] = time.Now()
+	metrics.SessionEnded()
+}
+
+// sweep expires idle sessions and forgets old ended ones. It must be called
+// with s.mu held.
+func (s *sessionStore) sweep(now time.Time) {
+	for id, sess := range s.sessions {
+		if s.idle > 0 && now.Sub(sess.lastSeen) > s.idle {
+			s.end(id, sess)
+		}
+	}
+	for id, at := range s.ended {
+		if now.Sub(at) > endedRetention {
+			delete(s.ended, id)
+		}
+	}
+}
+
+// config returns the upstream config stored for a session, or nil.
+func (s *sessionStore) config(id string) *config.APIConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[id]; ok {
+		return sess.cfg
+	}
+	return nil
+}
+
+// contextFunc runs for every POST once the MCP session is known. The first
+// one, for initialize, binds the session to the authenticated client, and
+// each records the config of the request for the session. Configs of a
+// CONFIG_FILE or vault profile are not recorded: they are resolved again for
+// every request, so a reload takes effect in running sessions.
+func (s *sessionStore) contextFunc(ctx context.Context, r *http.Request) context.Context {
+	clientSession := server.ClientSessionFromContext(ctx)
+	if clientSession == nil {
+		return ctx
+	}
+	id, _ := auth.FromContext(r.Context())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[clientSession.SessionID()]
+	if !ok {
+		return ctx
+	}
+	if !sess.bound {
+		sess.owner, sess.bound = id.Label, true
+	}
+	if cfg := config.FromContext(ctx, nil); cfg != nil && cfg.Profile == "" && id.Profile == "" {
+		sess.cfg = cfg
+	}
+	return ctx
+}
+
+// use checks that the client of r may use session id: it must exist and
+// belong to the client that started it, so another token holder who learns
+// the ID cannot use the session or its stored config.
+func (s *sessionStore) use(id string, r *http.Request) *sessionError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.useLocked(id, r)
+}
+
+// useLocked must be called with s.mu held.
+func (s *sessionStore) useLocked(id string, r *http.Request) *sessionError {
+	if sess, ok := s.sessions[id]; ok && sess.bound {
+		if client, _ := auth.FromContext(r.Context()); client.Label != sess.owner {
+			return &sessionError{http.StatusForbidden, "Session belongs to another client"}
+		}
+	}
+	if terminated, err := s.validate(id); err != nil || terminated {
+		return errSession(terminated, err)
+	}
+	return nil
+}
+
+// stream registers a GET stream of a session. The returned request is
+// canceled when the session is terminated; release must be called once the
+// stream ends.
+func (s *sessionStore) stream(id string, r *http.Request) (*http.Request, func(), *sessionError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e := s.useLocked(id, r); e != nil {
+		return nil, nil, e
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	r = r.WithContext(ctx)
+	sess := s.sessions[id]
+	sess.streams[r] = cancel
+	return r, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(sess.streams, r)
+		cancel()
+	}, nil
+}
+
+// sessionError is the HTTP response for a request naming a session that
+// cannot be used.
+type sessionError struct {
+	status int
+	msg    string
+}
+
+func errSession(terminated bool, err error) *sessionError {
+	if terminated {
+		return &sessionError{http.StatusNotFound, "Session terminated"}
+	}
+	return &sessionError{http.StatusBadRequest, "Invalid session ID"}
+}
+
+// upstreams resolves the upstream config of HTTP requests.
+type upstreams struct {
+	egress *egress.Policy // Rules for API_BASE_URL headers
+
+	mu       sync.RWMutex
+	base     *config.APIConfig          // Server-level settings and tool filters
+	profiles map[string]*config.Profile // Vault profiles by name, for clients bound to one
+}
+
+// set replaces the server-level config and vault profiles, e.g. on reload.
+func (u *upstreams) set(base *config.APIConfig, profiles map[string]*config.Profile) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.base, u.profiles = base, profiles
+}
+
+func (u *upstreams) get() (*config.APIConfig, map[string]*config.Profile) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.base, u.profiles
+}
+
+// config builds the upstream config for an HTTP request. Clients bound to a
+// vault profile always get that profile, and credential headers are ignored.
+// Otherwise the config comes from the request headers, and config returns
+// nil if there is no API_BASE_URL, in which case the config of the session
+// applies. Server-level settings come from base, but credentials never do:
+// callers only use the ones they send or their profile holds. Nor does
+// FILE_ROOT for a client-supplied base URL, which could otherwise be used to
+// upload the server's files to any host. An API_BASE_URL the egress policy
+// refuses is an *egress.Error.
+func (u *upstreams) config(r *http.Request) (*config.APIConfig, error) {
+	// Request headers can only narrow the tool set configured by the environment
+	requestFilter, err := config.LoadToolFilter(r.Header.Get)
+	if err != nil {
+		return nil, err
+	}
+	base, profiles := u.get()
+	if id, ok := auth.FromContext(r.Context()); ok && id.Profile != "" {
+		profile := profiles[id.Profile]
+		if profile == nil {
+			return nil, fmt.Errorf("unknown profile %q", id.Profile)
+		}
+		cfg := profile.APIConfig(base)
+		cfg.Tools = append(cfg.Tools, requestFilter)
+		return cfg, nil
+	}
+	baseURL := r.Header.Get("API_BASE_URL")
+	if baseURL == "" {
+		return nil, nil
+	}
+	if err := u.egress.Check(r.Context(), baseURL); err != nil {
+		return nil, err
+	}
+	cfg := &config.APIConfig{
+		BaseURL:     baseURL,
+		BearerToken: r.Header.Get("BEARER_TOKEN"),
+		Project:     r.Header.Get("X-Appwrite-Project"),
+		Key:         r.Header.Get("X-Appwrite-Key"),
+		JWT:         r.Header.Get("X-Appwrite-JWT"),
+		Locale:      r.Header.Get("X-Appwrite-Locale"),
+		BasicAuth:   r.Header.Get("BASIC_AUTH"),
+		Tools:       append(append([]config.ToolFilter{}, base.Tools...), requestFilter),
+		Untrusted:   true,
+	}
+	if cfg.Key == "" {
+		cfg.Key = r.Header.Get("API_KEY")
+	}
+	return cfg, nil
+}
+
+// fallback returns the config of the default profile from CONFIG_FILE for
+// authenticated requests that carry no config and have no session one, or
+// nil. Profiles are only available when the endpoint requires tokens.
+func (u *upstreams) fallback(r *http.Request) *config.APIConfig {
+	base, _ := u.get()
+	if _, ok := auth.FromContext(r.Context()); !ok || base.Profile == "" {
+		return nil
+	}
+	requestFilter, _ := config.LoadToolFilter(r.Header.Get)
+	cfg := *base
+	cfg.Tools = append(append([]config.ToolFilter{}, base.Tools...), base.Profiles[base.Profile].Tools, requestFilter)
+	return &cfg
+}
+
+// configError answers a request whose config could not be built: 403 for a
+// base URL the egress policy refuses, 400 otherwise.
+func configError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusBadRequest
+	if egress.IsBlocked(err) {
+		slog.WarnContext(r.Context(), "Refused request", "error", err)
+		status = http.StatusForbidden
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// logUpstream logs, at debug, the vault profile a request is bound to or
+// the base URL it supplied, without credentials.
+func logUpstream(r *http.Request, cfg *config.APIConfig, msg string) {
+	if id, ok := auth.FromContext(r.Context()); ok && id.Profile != "" {
+		slog.DebugContext(r.Context(), msg, "profile", id.Profile)
+	} else if r.Header.Get("API_BASE_URL") != "" {
+		slog.DebugContext(r.Context(), msg, "base_url", redact.URL(cfg.BaseURL))
+	}
+}
+
+// registerSecrets masks the credentials of cfg, which a client may have
+// sent as X-Appwrite-Key, API_KEY, X-Appwrite-JWT, BEARER_TOKEN or
+// BASIC_AUTH headers, until the returned function is called. Those of the
+// server and its profiles are registered for good when they are loaded.
+func registerSecrets(cfg *config.APIConfig) (release func()) {
+	return redact.Register(secrets(cfg, nil)...)
+}
+
+// mcpHandler serves the MCP endpoint of the long-lived streamable HTTP
+// server. It attaches the upstream config to each request and enforces the
+// session lifecycle for GET streams and DELETE.
+func mcpHandler(mcpSrv *server.MCPServer, up *upstreams, sessions *sessionStore) http.Handler {
+	streamable := server.NewStreamableHTTPServer(mcpSrv,
+		server.WithSessionIdManager(sessions),
+		server.WithHTTPContextFunc(sessions.contextFunc),
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if r.Method == http.MethodGet {
+			if sessionID == "" {
+				http.Error(w, "Missing "+server.HeaderKeySessionID+" header", http.StatusBadRequest)
+				return
+			}
+			stream, release, e := sessions.stream(sessionID, r)
+			if e != nil {
+				http.Error(w, e.msg, e.status)
+				return
+			}
+			defer release()
+			streamable.ServeHTTP(w, stream)
+			return
+		}
+		if sessionID != "" || r.Method == http.MethodDelete {
+			if e := sessions.use(sessionID, r); e != nil {
+				http.Error(w, e.msg, e.status)
+				return
+			}
+		}
+		if r.Method != http.MethodPost {
+			streamable.ServeHTTP(w, r)
+			return
+		}
+
+		cfg, err := up.config(r)
+		if err != nil {
+			configError(w, r, err)
+			return
+		}
+		if cfg == nil {
+			cfg = sessions.config(sessionID)
+		}
+		if cfg == nil {
+			cfg = up.fallback(r)
+		}
+		if cfg == nil {
+			http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
+			return
+		}
+		logUpstream(r, cfg, "Incoming HTTP request")
+		defer registerSecrets(cfg)()
+		streamable.ServeHTTP(w, r.WithContext(config.WithContext(r.Context(), cfg)))
+	})
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/egress"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/appwrite/mcp-server/vault"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestMCPHandlerRefusesPrivateBaseURL(t *testing.T) {
+	base := &config.APIConfig{}
+	up := &upstreams{egress: &egress.Policy{Allow: []string{"https://*.appwrite.io"}}}
+	up.set(base, nil)
+	mcpSrv, _ := createMCPServer(base, "HTTP", GetAll)
+	h := mcpHandler(mcpSrv, up, newSessionStore(0))
+
+	for _, baseURL := range []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://localhost/v1",
+		"https://appwrite.example.com/v1",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		req.Header.Set("API_BASE_URL", baseURL)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want 403", baseURL, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "is not allowed") {
+			t.Errorf("%s: body = %q", baseURL, rec.Body.String())
+		}
+	}
+}
+
+func TestClientBaseURLHasNoFileAccess(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+	up := &upstreams{egress: &egress.Policy{AllowNetwork: []*net.IPNet{loopback}}}
+	up.set(&config.APIConfig{FileRoot: t.TempDir()}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("API_BASE_URL", "http://127.0.0.1:8080/v1")
+	cfg, err := up.config(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.FileRoot != "" {
+		t.Errorf("FileRoot = %q for a client-supplied base URL", cfg.FileRoot)
+	}
+}
+
+// authenticated runs r through auth.Require as the client of token and
+// returns the request the protected handler sees.
+func authenticated(t *testing.T, tokens *auth.Tokens, token string, r *http.Request) *http.Request {
+	t.Helper()
+	var seen *http.Request
+	r.Header.Set("Authorization", "Bearer "+token)
+	auth.Require(tokens, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) { seen = r })).ServeHTTP(httptest.NewRecorder(), r)
+	if seen == nil {
+		t.Fatal("request not authenticated")
+	}
+	return seen
+}
+
+func TestVaultClientIsBoundToItsProfile(t *testing.T) {
+	v, err := vault.Parse([]byte(`clients:
+  - {label: ci, token: ci-token, profile: prod}
+profiles:
+  prod: {baseURL: "https://prod.example.com/v1", project: prod, key: prod-key}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := &auth.Tokens{}
+	tokens.Add(auth.Identity{Label: "ci", Profile: "prod"}, "ci-token")
+	base := &config.APIConfig{
+		Profile:  "staging",
+		Profiles: map[string]*config.Profile{"staging": {BaseURL: "https://staging.example.com/v1", Project: "staging"}},
+	}
+	up := &upstreams{egress: &egress.Policy{}}
+	up.set(base, v.Profiles)
+
+	// Credential headers cannot replace the profile's upstream or key
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("API_BASE_URL", "https://attacker.example.com/v1")
+	req.Header.Set("X-Appwrite-Key", "other-key")
+	req.Header.Set("X-Appwrite-Project", "other")
+	req = authenticated(t, tokens, "ci-token", req)
+	cfg, err := up.config(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BaseURL != "https://prod.example.com/v1" || cfg.Key != "prod-key" || cfg.Project != "prod" || cfg.Untrusted {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	// Nor can the profile argument switch to another profile
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{profileParam: "staging"}
+	ctx := config.WithContext(req.Context(), cfg)
+	if _, _, _, err := withProfile(ctx, request, base); err == nil || !strings.Contains(err.Error(), "vault profile") {
+		t.Errorf("profile argument: err = %v", err)
+	}
+}
+
+// sessionServer returns an MCP handler whose "probe" tool answers with the
+// base URL of the upstream config it was called with.
+func sessionServer(idle time.Duration) (http.Handler, *sessionStore) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+	up := &upstreams{egress: &egress.Policy{AllowNetwork: []*net.IPNet{loopback}}}
+	up.set(&config.APIConfig{}, nil)
+	mcpSrv := server.NewMCPServer("test", "0")
+	mcpSrv.AddTool(mcp.NewTool("probe"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(config.FromContext(ctx, &config.APIConfig{}).BaseURL), nil
+	})
+	sessions := newSessionStore(idle)
+	return mcpHandler(mcpSrv, up, sessions), sessions
+}
+
+func mcpRequest(method, sessionID, body string) *http.Request {
+	req := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(server.HeaderKeySessionID, sessionID)
+	}
+	return req
+}
+
+// initialize starts a session with the given base URL and returns its ID.
+func initialize(t *testing.T, h http.Handler, baseURL string) string {
+	t.Helper()
+	req := mcpRequest(http.MethodPost, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`)
+	req.Header.Set("API_BASE_URL", baseURL)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	id := rec.Header().Get(server.HeaderKeySessionID)
+	if rec.Code != http.StatusOK || id == "" {
+		t.Fatalf("initialize: status = %d, session = %q, body = %s", rec.Code, id, rec.Body)
+	}
+	return id
+}
+
+const ping = `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+
+func TestSessionLifecycle(t *testing.T) {
+	h, _ := sessionServer(0)
+	id := initialize(t, h, "http://127.0.0.1:8080/v1")
+
+	// Later requests may omit API_BASE_URL and use the session config
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodPost, id, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"probe"}}`))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "http://127.0.0.1:8080/v1") {
+		t.Errorf("tools/call: status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodGet, "", ""))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GET without session: status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodPost, "mcp-session-unknown", ping))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown session: status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodDelete, id, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE: status = %d, body = %s", rec.Code, rec.Body)
+	}
+	for _, method := range []string{http.MethodPost, http.MethodGet, http.MethodDelete} {
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, mcpRequest(method, id, ping))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s after DELETE: status = %d, want 404", method, rec.Code)
+		}
+	}
+}
+
+func TestSessionIdleExpiry(t *testing.T) {
+	h, _ := sessionServer(20 * time.Millisecond)
+	id := initialize(t, h, "http://127.0.0.1:8080/v1")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodPost, id, ping))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ping: status = %d, body = %s", rec.Code, rec.Body)
+	}
+	time.Sleep(40 * time.Millisecond)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodPost, id, ping))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("ping after idle timeout: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestTerminateClosesStreams(t *testing.T) {
+	h, sessions := sessionServer(0)
+	id := initialize(t, h, "http://127.0.0.1:8080/v1")
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/mcp", nil)
+	req.Header.Set(server.HeaderKeySessionID, id)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET: status = %d", resp.StatusCode)
+	}
+
+	if _, err := sessions.Terminate(id); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, resp.Body)
+		done <- err
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GET stream still open after the session was terminated")
+	}
+}
+
+// as sends every request to h with token.
+func as(token string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+token)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestSessionBoundToClient(t *testing.T) {
+	tokens := &auth.Tokens{}
+	tokens.Add(auth.Identity{Label: "owner"}, "owner-token")
+	tokens.Add(auth.Identity{Label: "other"}, "other-token")
+	h, _ := sessionServer(0)
+	h = auth.Require(tokens, h)
+	owner, other := as("owner-token", h), as("other-token", h)
+	id := initialize(t, owner, "http://127.0.0.1:8080/v1")
+
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"probe"}}`
+	for _, method := range []string{http.MethodPost, http.MethodGet, http.MethodDelete} {
+		rec := httptest.NewRecorder()
+		other.ServeHTTP(rec, mcpRequest(method, id, call))
+		if rec.Code != http.StatusForbidden || strings.Contains(rec.Body.String(), "127.0.0.1") {
+			t.Errorf("%s by another client: status = %d, body = %s", method, rec.Code, rec.Body)
+		}
+	}
+
+	// The session is still the owner's, with its config
+	rec := httptest.NewRecorder()
+	owner.ServeHTTP(rec, mcpRequest(http.MethodPost, id, call))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "http://127.0.0.1:8080/v1") {
+		t.Errorf("owner: status = %d, body = %s", rec.Code, rec.Body)
+	}
+}
+
+func TestClientCredentialsAreRedacted(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+	up := &upstreams{egress: &egress.Policy{AllowNetwork: []*net.IPNet{loopback}}}
+	up.set(&config.APIConfig{}, nil)
+	mcpSrv := server.NewMCPServer("test", "0")
+	// echo returns the credentials of its config as logs would show them
+	mcpSrv.AddTool(mcp.NewTool("echo"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg := config.FromContext(ctx, &config.APIConfig{})
+		return mcp.NewToolResultText(redact.String(strings.Join([]string{cfg.Key, cfg.JWT, cfg.BearerToken, cfg.BasicAuth}, ","))), nil
+	})
+	h := mcpHandler(mcpSrv, up, newSessionStore(0))
+	const call = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo"}}`
+	masked := strings.Repeat(redact.Mask+",", 3) + redact.Mask
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"X-Appwrite-Key", map[string]string{"X-Appwrite-Key": "client-key-0001", "X-Appwrite-JWT": "client-jwt-0001", "BEARER_TOKEN": "client-bearer-0001", "BASIC_AUTH": "client:password-0001"}},
+		{"API_KEY", map[string]string{"API_KEY": "client-key-0002", "X-Appwrite-JWT": "client-jwt-0002", "BEARER_TOKEN": "client-bearer-0002", "BASIC_AUTH": "client:password-0002"}},
+	}
+	id := initialize(t, h, "http://127.0.0.1:8080/v1")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcpRequest(http.MethodPost, id, call)
+			req.Header.Set("API_BASE_URL", "http://127.0.0.1:8080/v1")
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if !strings.Contains(rec.Body.String(), masked) {
+				t.Errorf("during the request: %s", rec.Body)
+			}
+			for _, v := range tt.headers {
+				if strings.Contains(rec.Body.String(), v) || redact.String(v) != v {
+					t.Errorf("%s: masked %q after the request, or leaked: %s", v, redact.String(v), rec.Body)
+				}
+			}
+		})
+	}
+
+	// Requests that use the config of their session mask its credentials too
+	init := mcpRequest(http.MethodPost, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`)
+	init.Header.Set("API_BASE_URL", "http://127.0.0.1:8080/v1")
+	init.Header.Set("X-Appwrite-Key", "session-key-0001")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, init)
+	id = rec.Header().Get(server.HeaderKeySessionID)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodPost, id, call))
+	if !strings.Contains(rec.Body.String(), redact.Mask+",,,") || strings.Contains(rec.Body.String(), "session-key-0001") {
+		t.Errorf("session config: %s", rec.Body)
+	}
+}
@@ -0,0 +1,163 @@
+// Package limit rate limits tool calls with a token bucket per client and
+// tool category, so one runaway client cannot flood Appwrite. Read-only
+// tools and all other tools have separate budgets, typically with a
+// stricter one for writes.
+package limit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sweepEvery is how often buckets that have refilled completely are
+// dropped, which bounds memory to the clients active within a refill period.
+const sweepEvery = time.Minute
+
+type key struct {
+	client string
+	write  bool
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter holds the buckets of every client.
+type Limiter struct {
+	reads, writes config.Rate
+	now           func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[key]*bucket
+	lastSweep time.Time
+}
+
+// New returns a limiter granting each client reads for read-only tools and
+// writes for the others. A zero Rate leaves that category unlimited.
+func New(reads, writes config.Rate) *Limiter {
+	return &Limiter{reads: reads, writes: writes, now: time.Now, buckets: map[key]*bucket{}}
+}
+
+// Allow takes a token from the bucket of client for the category. If the
+// bucket is empty it returns false and how long until a token is available.
+func (l *Limiter) Allow(client string, write bool) (bool, time.Duration) {
+	rate := l.reads
+	if write {
+		rate = l.writes
+	}
+	if rate.Count == 0 {
+		return true, 0
+	}
+	perToken := rate.Per.Seconds() / float64(rate.Count)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepEvery {
+		l.sweep(now)
+	}
+	k := key{client, write}
+	b := l.buckets[k]
+	if b == nil {
+		b = &bucket{tokens: float64(rate.Count), last: now}
+		l.buckets[k] = b
+	}
+	b.tokens = math.Min(float64(rate.Count), b.tokens+now.Sub(b.last).Seconds()/perToken)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) * perToken * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops buckets that are full again, as a fresh bucket is equivalent.
+func (l *Limiter) sweep(now time.Time) {
+	for k, b := range l.buckets {
+		rate := l.reads
+		if k.write {
+			rate = l.writes
+		}
+		if now.Sub(b.last) >= rate.Per {
+			delete(l.buckets, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+var defaultLimiter atomic.Pointer[Limiter]
+
+// SetDefault sets the limiter Instrument applies. Until it is called, tool
+// calls are not limited.
+func SetDefault(l *Limiter) {
+	defaultLimiter.Store(l)
+}
+
+// Client identifies the caller in ctx for rate limiting: the label of an
+// authenticated client, else the MCP session. Without authentication a
+// client can evade its limit by opening new sessions.
+func Client(ctx context.Context) string {
+	if id, ok := auth.FromContext(ctx); ok {
+		return "client:" + id.Label
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return "session:" + session.SessionID()
+	}
+	return "anonymous"
+}
+
+// Instrument wraps the handler of tool so calls beyond the budget of the
+// caller fail with a rate limit error telling when to retry.
+func Instrument(tool models.Tool) models.Tool {
+	write := tool.Definition.Annotations.ReadOnlyHint == nil || !*tool.Definition.Annotations.ReadOnlyHint
+	next := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		l := defaultLimiter.Load()
+		if l == nil {
+			return next(ctx, request)
+		}
+		if ok, wait := l.Allow(Client(ctx), write); !ok {
+			return limited(write, l, wait), nil
+		}
+		return next(ctx, request)
+	}
+	return tool
+}
+
+// limited is the tool error for a rate limited call. Its structured content
+// has the shape of an Appwrite error, so clients handle both alike.
+func limited(write bool, l *Limiter, wait time.Duration) *mcp.CallToolResult {
+	category, rate := "read-only", l.reads
+	if write {
+		category, rate = "write", l.writes
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	e := &appwrite.Error{
+		Status:     http.StatusTooManyRequests,
+		Type:       "mcp_rate_limit_exceeded",
+		Message:    fmt.Sprintf("Rate limit for %s tools exceeded: at most %d calls per %s", category, rate.Count, rate.Per),
+		Retryable:  true,
+		RetryAfter: strconv.Itoa(seconds),
+		Hint:       fmt.Sprintf("Wait %d seconds before calling %s tools again, and slow down.", seconds, category),
+	}
+	return &mcp.CallToolResult{
+		IsError:           true,
+		Content:           []mcp.Content{mcp.NewTextContent(fmt.Sprintf("%s. Retry after %d seconds.", e.Message, seconds))},
+		StructuredContent: e,
+	}
+}
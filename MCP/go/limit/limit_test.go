@@ -0,0 +1,84 @@
+package limit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := New(config.Rate{Count: 3, Per: time.Minute}, config.Rate{Count: 1, Per: time.Minute})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("a", false); !ok {
+			t.Fatalf("read %d limited", i)
+		}
+	}
+	if ok, wait := l.Allow("a", false); ok || wait != 20*time.Second {
+		t.Errorf("4th read: ok = %v, wait = %s, want 20s", ok, wait)
+	}
+	// Writes and other clients have their own buckets
+	if ok, _ := l.Allow("a", true); !ok {
+		t.Error("first write limited")
+	}
+	if ok, wait := l.Allow("a", true); ok || wait != time.Minute {
+		t.Errorf("2nd write: ok = %v, wait = %s, want 1m", ok, wait)
+	}
+	if ok, _ := l.Allow("b", false); !ok {
+		t.Error("other client limited")
+	}
+
+	now = now.Add(20 * time.Second)
+	if ok, _ := l.Allow("a", false); !ok {
+		t.Error("read after refill limited")
+	}
+	if ok, _ := l.Allow("a", false); ok {
+		t.Error("refilled more than one token")
+	}
+
+	// Idle buckets are dropped once full again
+	now = now.Add(2 * time.Minute)
+	l.Allow("c", false)
+	if len(l.buckets) != 1 {
+		t.Errorf("%d buckets after sweep, want 1", len(l.buckets))
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	SetDefault(New(config.Rate{}, config.Rate{Count: 1, Per: time.Hour}))
+	defer SetDefault(nil)
+
+	calls := 0
+	handler := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	}
+	read := Instrument(models.Tool{Definition: mcp.NewTool("get_users", mcp.WithReadOnlyHintAnnotation(true)), Handler: handler})
+	write := Instrument(models.Tool{Definition: mcp.NewTool("delete_users_userId", mcp.WithReadOnlyHintAnnotation(false)), Handler: handler})
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if result, _ := read.Handler(ctx, mcp.CallToolRequest{}); result.IsError {
+			t.Fatalf("read %d limited", i)
+		}
+	}
+	write.Handler(ctx, mcp.CallToolRequest{})
+	result, err := write.Handler(ctx, mcp.CallToolRequest{})
+	if err != nil || !result.IsError {
+		t.Fatalf("second write: %v, %+v", err, result)
+	}
+	e, ok := result.StructuredContent.(*appwrite.Error)
+	if !ok || e.Status != 429 || !e.Retryable || e.RetryAfter != "3600" {
+		t.Errorf("structured content = %+v", result.StructuredContent)
+	}
+	if calls != 6 {
+		t.Errorf("handler ran %d times, want 6", calls)
+	}
+}
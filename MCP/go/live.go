@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/appwrite/mcp-server/audit"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/limit"
+	"github.com/appwrite/mcp-server/logging"
+	"github.com/appwrite/mcp-server/metrics"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/tracing"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// liveTools keeps the tools registered on a long-lived MCP server in step
+// with the config, so a reload takes effect without dropping sessions.
+// Registered handlers look the tool up in the current state on every call,
+// and definitions are only registered again when they change, which is when
+// clients get notifications/tools/list_changed.
+type liveTools struct {
+	mcpSrv *server.MCPServer
+	mu     sync.Mutex // Serializes updates
+	state  atomic.Pointer[toolState]
+}
+
+type toolState struct {
+	cfg   *config.APIConfig
+	tools map[string]models.Tool
+	defs  map[string]string // JSON of each registered definition, to detect changes
+}
+
+// update builds the tools for cfg and registers the ones that are new or
+// changed, removing those no longer exposed. It returns how many tools are
+// exposed and how many were added, changed and removed.
+func (l *liveTools) update(cfg *config.APIConfig, getTools func(*config.APIConfig) []models.Tool) (total, added, changed, removed int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	next := &toolState{cfg: cfg, tools: map[string]models.Tool{}, defs: map[string]string{}}
+	prev := l.state.Load()
+	if prev == nil {
+		prev = &toolState{}
+	}
+	var register []server.ServerTool
+	for _, tool := range filterTools(getTools(cfg), cfg.Tools) {
+		name := tool.Definition.Name
+		def := withProfileParam(tool.Definition, cfg)
+		encoded, err := json.Marshal(def)
+		if err != nil {
+			panic(fmt.Sprintf("encode tool %s: %v", name, err))
+		}
+		guarded := tool
+		guarded.Handler = guard(limit.Instrument(tool))
+		next.tools[name] = metrics.Instrument(tracing.Instrument(logging.Instrument(audit.Instrument(guarded))))
+		next.defs[name] = string(encoded)
+		if old, ok := prev.defs[name]; !ok || old != string(encoded) {
+			register = append(register, server.ServerTool{Tool: def, Handler: l.handler(name)})
+			if ok {
+				changed++
+			} else {
+				added++
+			}
+		}
+	}
+	var gone []string
+	for name := range prev.defs {
+		if _, ok := next.defs[name]; !ok {
+			gone = append(gone, name)
+		}
+	}
+
+	// Calls see the new config from here on, even for unchanged tools
+	l.state.Store(next)
+	if len(gone) > 0 {
+		l.mcpSrv.DeleteTools(gone...)
+	}
+	if len(register) > 0 {
+		l.mcpSrv.AddTools(register...)
+	}
+	return len(next.tools), added, changed, len(gone)
+}
+
+// config returns the current server-level config.
+func (l *liveTools) config() *config.APIConfig {
+	return l.state.Load().cfg
+}
+
+// handler calls the current version of the named tool.
+func (l *liveTools) handler(name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		state := l.state.Load()
+		tool, ok := state.tools[name]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Tool %s is no longer available", name)), nil
+		}
+		// SSE messages are handled after their request returned, so the call
+		// keeps the credentials of its config masked itself
+		if reqCfg := config.FromContext(ctx, nil); reqCfg != nil {
+			defer registerSecrets(reqCfg)()
+		}
+		ctx, request = selectProfile(ctx, request, state.cfg)
+		return tool.Handler(ctx, request)
+	}
+}
+
+// filter narrows tools/list to the tools the config of an HTTP request
+// allows.
+func (l *liveTools) filter(ctx context.Context, list []mcp.Tool) []mcp.Tool {
+	reqCfg := config.FromContext(ctx, nil)
+	if reqCfg == nil {
+		return list
+	}
+	state := l.state.Load()
+	var kept []mcp.Tool
+	for _, tool := range list {
+		if allowed(state.tools[tool.Name], reqCfg.Tools) {
+			kept = append(kept, tool)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/metrics"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolErrors returns the value of the tool error counter of tool for calls
+// that never reached Appwrite.
+func toolErrors(t *testing.T, tool string) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	m := regexp.MustCompile(`appwrite_mcp_tool_errors_total\{status="none",tool="` + tool + `"\} (\d+)`).FindSubmatch(body)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(string(m[1]))
+	return n
+}
+
+func TestGuardedRejectionsAreInstrumented(t *testing.T) {
+	calls := 0
+	tool := models.Tool{
+		Definition: mcp.NewTool("get_guarded", mcp.WithReadOnlyHintAnnotation(true)),
+		Service:    "teams",
+		Handler: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls++
+			return mcp.NewToolResultText("{}"), nil
+		},
+	}
+	base := &config.APIConfig{
+		Profile:  "prod",
+		Profiles: map[string]*config.Profile{"prod": {BaseURL: "https://prod.example.com/v1"}},
+	}
+	_, live := createMCPServer(base, "HTTP", func(*config.APIConfig) []models.Tool { return []models.Tool{tool} })
+	handler := live.handler("get_guarded")
+
+	for _, tc := range []struct {
+		name, profile, want string
+		reqCfg              *config.APIConfig
+	}{
+		{"unknown profile", "staging", `unknown profile "staging"`, nil},
+		{"filtered tool", "", "Tool get_guarded is not enabled for this session", &config.APIConfig{Tools: []config.ToolFilter{{Deny: []string{"get_*"}}}}},
+	} {
+		before := toolErrors(t, "get_guarded")
+		ctx := context.Background()
+		if tc.reqCfg != nil {
+			ctx = config.WithContext(ctx, tc.reqCfg)
+		}
+		var request mcp.CallToolRequest
+		request.Params.Arguments = map[string]any{}
+		if tc.profile != "" {
+			request.Params.Arguments = map[string]any{profileParam: tc.profile}
+		}
+		result, err := handler(ctx, request)
+		if err != nil || !result.IsError || models.ResultText(result) != tc.want {
+			t.Errorf("%s: result %+v, err %v", tc.name, result, err)
+		}
+		if got := toolErrors(t, "get_guarded"); got != before+1 {
+			t.Errorf("%s: tool errors %d, want %d", tc.name, got, before+1)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("refused calls reached the tool %d times", calls)
+	}
+}
+
+func TestCallsMaskClientCredentials(t *testing.T) {
+	tool := models.Tool{
+		Definition: mcp.NewTool("get_echo", mcp.WithReadOnlyHintAnnotation(true)),
+		Handler: func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(redact.String("key " + config.FromContext(ctx, &config.APIConfig{}).Key)), nil
+		},
+	}
+	_, live := createMCPServer(&config.APIConfig{}, "SSE", func(*config.APIConfig) []models.Tool { return []models.Tool{tool} })
+	ctx := config.WithContext(context.Background(), &config.APIConfig{BaseURL: "http://127.0.0.1:8080/v1", Key: "sse-key-0001", Untrusted: true})
+	var request mcp.CallToolRequest
+	request.Params.Arguments = map[string]any{}
+
+	// SSE messages are handled after their request, so the call masks them
+	result, err := live.handler("get_echo")(ctx, request)
+	if err != nil || models.ResultText(result) != "key "+redact.Mask {
+		t.Errorf("result %q, err %v", models.ResultText(result), err)
+	}
+	if got := redact.String("sse-key-0001"); got != "sse-key-0001" {
+		t.Errorf("still masked after the call: %q", got)
+	}
+}
@@ -0,0 +1,202 @@
+// Package logging sets up structured logs with log/slog. Every line passes
+// through redact, and lines logged with a request context carry its request
+// and session IDs and the active trace.
+//
+// LOG_LEVEL is one of debug, info (the default), warn or error. LOG_FORMAT
+// is json (the default) or text.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID. A well-formed one sent by the
+// client or a proxy is kept, otherwise one is generated.
+const RequestIDHeader = "X-Request-ID"
+
+// Setup makes a logger writing to w, configured by LOG_LEVEL and LOG_FORMAT
+// as read by get, the default for slog and for the log package.
+func Setup(get func(string) string, w io.Writer) error {
+	var level slog.Level
+	if val := get("LOG_LEVEL"); val != "" {
+		if err := level.UnmarshalText([]byte(val)); err != nil {
+			return fmt.Errorf("invalid LOG_LEVEL %q: use debug, info, warn or error", val)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: replaceAttr}
+	var h slog.Handler
+	switch format := strings.ToLower(get("LOG_FORMAT")); format {
+	case "", "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q: use json or text", format)
+	}
+	slog.SetDefault(slog.New(handler{h}))
+	// Lines from the log package, including those of libraries, now go to
+	// the handler at info, which adds its own timestamp
+	log.SetFlags(0)
+	return nil
+}
+
+// replaceAttr masks attributes with sensitive names and scrubs secrets from
+// every other string, including the message.
+func replaceAttr(_ []string, a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.TimeKey, slog.LevelKey, slog.SourceKey:
+		return a
+	}
+	if redact.Sensitive(a.Key) {
+		return slog.String(a.Key, redact.Mask)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redact.String(a.Value.String()))
+	case slog.KindAny:
+		if args, ok := a.Value.Any().(map[string]any); ok {
+			return slog.Any(a.Key, redact.Args(args))
+		}
+		return slog.String(a.Key, redact.String(fmt.Sprint(a.Value.Any())))
+	}
+	return a
+}
+
+type contextKey struct{}
+
+// WithAttrs returns a copy of ctx whose log lines carry attrs in addition to
+// those already in ctx.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return context.WithValue(ctx, contextKey{}, append(prev[:len(prev):len(prev)], attrs...))
+}
+
+// handler adds the attributes of the context and its trace to each record.
+type handler struct {
+	slog.Handler
+}
+
+func (h handler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(contextKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return handler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h handler) WithGroup(name string) slog.Handler {
+	return handler{h.Handler.WithGroup(name)}
+}
+
+// Middleware gives each request an ID, returned in the X-Request-ID
+// header, and puts it in the request context with the MCP session ID the
+// request names, if any.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validID(id) {
+			id = newID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		attrs := []slog.Attr{slog.String("request_id", id)}
+		// Streamable HTTP names the session in a header, SSE in the query
+		session := r.Header.Get(server.HeaderKeySessionID)
+		if session == "" {
+			session = r.URL.Query().Get("sessionId")
+		}
+		if validID(session) {
+			attrs = append(attrs, slog.String("session_id", session))
+		}
+		next.ServeHTTP(w, r.WithContext(WithAttrs(r.Context(), attrs...)))
+	})
+}
+
+// validID reports whether id is safe to log: short and free of characters
+// that could forge log lines.
+func validID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Instrument wraps the handler of tool to log each call with its duration,
+// outcome, client and profile. Calls that fail are logged at warn with the error.
+func Instrument(tool models.Tool) models.Tool {
+	name := tool.Definition.Name
+	next := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := ctx.Value(contextKey{}).([]slog.Attr); !ok {
+			// STDIO has no HTTP request to take the session from
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				ctx = WithAttrs(ctx, slog.String("session_id", session.SessionID()))
+			}
+		}
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		attrs := []slog.Attr{
+			slog.String("tool", name),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		}
+		profile := ""
+		if id, ok := auth.FromContext(ctx); ok {
+			attrs = append(attrs, slog.String("client", id.Label))
+			profile = id.Profile
+		}
+		if cfg := config.FromContext(ctx, nil); cfg != nil && cfg.Profile != "" {
+			profile = cfg.Profile
+		}
+		if profile != "" {
+			attrs = append(attrs, slog.String("profile", profile))
+		}
+		level := slog.LevelInfo
+		switch {
+		case err != nil:
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("status", "error"), slog.String("error", redact.Echoes(err.Error(), request.GetArguments())))
+		case result != nil && result.IsError:
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("status", "error"), slog.String("error", redact.Echoes(models.ResultText(result), request.GetArguments())))
+		default:
+			attrs = append(attrs, slog.String("status", "ok"))
+		}
+		slog.LogAttrs(ctx, level, "tool call", attrs...)
+		return result, err
+	}
+	return tool
+}
@@ -1,8 +1,11 @@
 package main
 
+//go:generate go run ./cmd/mcpgen -spec ../../openapi.yaml
+
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -10,68 +13,141 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/audit"
+	"github.com/appwrite/mcp-server/auth"
 	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/limit"
+	"github.com/appwrite/mcp-server/logging"
+	"github.com/appwrite/mcp-server/metrics"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/openapi"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/appwrite/mcp-server/tracing"
 )
 
 func main() {
-	cfg, err := config.LoadAPIConfig()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
-	}
-
 	// Check transport environment variable (both uppercase and lowercase)
 	transport := os.Getenv("TRANSPORT")
 	if transport == "" {
 		transport = os.Getenv("transport")
 	}
+	remote := transport == "http" || transport == "HTTP" || transport == "https" || transport == "HTTPS" || transport == "sse" || transport == "SSE"
+
+	if err := logging.Setup(os.Getenv, os.Stderr); err != nil {
+		fatal("Failed to set up logging", "error", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(replay(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	st, err := loadState(remote)
+	if err != nil {
+		fatal("Failed to load config", "error", err)
+	}
+	redact.Secret(st.secrets...)
+	cfg := st.cfg
+	clientCfg, err := config.LoadClientConfig()
+	if err != nil {
+		fatal("Failed to load HTTP client config", "error", err)
+	}
+	appwrite.SetDefault(appwrite.NewClient(clientCfg))
+
+	// Spans still buffered when a shutdown signal arrives are flushed on return
+	shutdownTracing, err := tracing.Setup(context.Background(), !remote)
+	if err != nil {
+		fatal("Failed to set up tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Tracing shutdown error", "error", err)
+		}
+	}()
+
+	applyLimits(cfg)
+
+	// Audit settings are read once; changing them requires a restart
+	if cfg.AuditLog != "" {
+		auditLog, err := audit.Open(cfg.AuditLog, cfg.AuditMaxBytes, cfg.AuditMaxFiles)
+		if err != nil {
+			fatal("Failed to open audit log", "error", err)
+		}
+		defer auditLog.Close()
+		audit.SetDefault(auditLog)
+		slog.Info("Writing audit log", "file", cfg.AuditLog)
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// HTTP/HTTPS Mode - if transport is "http", "HTTP", "https", or "HTTPS"
-	if transport == "http" || transport == "HTTP" || transport == "https" || transport == "HTTPS" {
+	// Metrics get their own listener when METRICS_PORT is set, e.g. to keep
+	// them off a public port; in STDIO mode that is the only way to serve them
+	if cfg.MetricsPort != "" {
+		go serveMetrics(cfg.MetricsPort)
+	}
+
+	// HTTP/HTTPS/SSE Mode - if transport is "http", "HTTP", "https", "HTTPS", "sse" or "SSE"
+	if remote {
 		port := cfg.Port
 		if port == "" {
-			log.Fatalf("PORT environment variable is required for HTTP/HTTPS mode. Please set PORT environment variable.")
+			fatal("PORT environment variable is required for HTTP/HTTPS/SSE mode. Please set PORT environment variable.")
 		}
 
-		// Determine if HTTPS mode and normalize transport
-		isHTTPS := transport == "https" || transport == "HTTPS"
-		if isHTTPS {
+		// Determine if HTTPS or SSE mode and normalize transport. SSE is
+		// served over TLS when a certificate is configured.
+		isSSE := transport == "sse" || transport == "SSE"
+		isHTTPS := transport == "https" || transport == "HTTPS" || (isSSE && os.Getenv("CERT_FILE") != "" && os.Getenv("KEY_FILE") != "")
+		if isSSE {
+			transport = "SSE"
+		} else if isHTTPS {
 			transport = "HTTPS"
 		} else {
 			transport = "HTTP"
 		}
 		
-		log.Printf("Running in %s mode on port %s", transport, port)
-
-		mux := http.NewServeMux()
-		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-			// Read headers for dynamic config
-			apiCfg := &config.APIConfig{
-				BaseURL:     r.Header.Get("API_BASE_URL"),
-				BearerToken: r.Header.Get("BEARER_TOKEN"),
-				APIKey:      r.Header.Get("API_KEY"),
-				BasicAuth:   r.Header.Get("BASIC_AUTH"),
-			}
+		slog.Info("Running in "+transport+" mode", "port", port)
 
-			if apiCfg.BaseURL == "" {
-				http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
-				return
-			}
-
-			log.Printf("Incoming HTTP request - BaseURL: %s", apiCfg.BaseURL)
+		// Clients must authenticate when a token file or a vault is configured
+		up := &upstreams{egress: clientCfg.Egress}
+		up.set(cfg, st.profiles)
+		tokens := st.tokens
+		protect := requireTokens(tokens)
+		if tokens.Len() > 0 {
+			slog.Info("Loaded tokens for the MCP endpoint", "count", tokens.Len())
+		} else {
+			slog.Warn("AUTH_TOKENS_FILE is not set; the MCP endpoint accepts unauthenticated requests")
+		}
 
-			// Create MCP server for this request
-			mcpSrv := createMCPServer(apiCfg, transport)
-			handler := server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(
-				func(ctx context.Context, req *http.Request) context.Context {
-					return context.WithValue(ctx, "apiConfig", apiCfg)
-				},
-			))
+		// One long-lived MCP server serves every session; each request
+		// carries its upstream config through the context.
+		mux := http.NewServeMux()
+		endpoint := "/mcp"
+		var mcpSrv *server.MCPServer
+		var live *liveTools
+		if isSSE {
+			endpoint = "/sse"
+			cfgs := newSSEConfigs()
+			hooks := cfgs.hooks()
+			metrics.AddSessionHooks(hooks)
+			mcpSrv, live = createMCPServer(cfg, transport, st.getTools, server.WithHooks(hooks))
+			sse, message := sseHandlers(mcpSrv, up, cfgs)
+			mux.Handle("/sse", protect(sse))
+			mux.Handle("/message", protect(message))
+		} else {
+			sessions := newSessionStore(cfg.SessionIdleTimeout)
+			mcpSrv, live = createMCPServer(cfg, transport, st.getTools)
+			mux.Handle("/mcp", protect(mcpHandler(mcpSrv, up, sessions)))
+		}
+		if tokens.Len() > 0 {
+			mux.Handle(auth.MetadataPath, auth.MetadataHandler(cfg.AuthResourceURL, endpoint, cfg.AuthServers))
+		}
+		go (&reloader{remote: true, live: live, up: up, tokens: tokens}).watch()
 
-			handler.ServeHTTP(w, r)
-		})
+		handleMetrics(mux, cfg, protect)
 
 		mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -79,7 +155,7 @@ func main() {
 		})
 
 		addr := net.JoinHostPort("0.0.0.0", port)
-		httpServer := &http.Server{Addr: addr, Handler: mux}
+		httpServer := &http.Server{Addr: addr, Handler: logging.Middleware(mux)}
 
 		go func() {
 			// Check if HTTPS mode
@@ -88,58 +164,179 @@ func main() {
 				keyFile := os.Getenv("KEY_FILE")
 				
 				if certFile == "" || keyFile == "" {
-					log.Fatalf("CERT_FILE and KEY_FILE environment variables are required for HTTPS mode")
+					fatal("CERT_FILE and KEY_FILE environment variables are required for HTTPS mode")
 				}
 				
-				log.Printf("Starting HTTPS server on %s", addr)
+				slog.Info("Starting HTTPS server", "addr", addr)
 				if err := httpServer.ListenAndServeTLS(certFile, keyFile); err != http.ErrServerClosed {
-					log.Fatalf("HTTPS server error: %v", err)
+					fatal("HTTPS server error", "error", err)
 				}
 			} else {
-				log.Printf("Starting HTTP server on %s", addr)
+				slog.Info("Starting HTTP server", "addr", addr)
 				if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
-					log.Fatalf("HTTP server error: %v", err)
+					fatal("HTTP server error", "error", err)
 				}
 			}
 		}()
 
 		<-sigChan
-		log.Println("Shutdown signal received")
+		slog.Info("Shutdown signal received")
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := httpServer.Shutdown(ctx); err != nil {
-			log.Printf("Shutdown error: %v", err)
+			slog.Error("Shutdown error", "error", err)
 		} else {
-			log.Println("HTTP server shutdown complete")
+			slog.Info("HTTP server shutdown complete")
 		}
 		return
 	}
 
 	// STDIO Mode - default when no transport or transport is "stdio"
-	log.Println("Running in STDIO mode")
-	mcp := createMCPServer(cfg, "STDIO")
+	slog.Info("Running in STDIO mode")
+	hooks := &server.Hooks{}
+	metrics.AddSessionHooks(hooks)
+	mcpSrv, live := createMCPServer(cfg, "STDIO", st.getTools, server.WithHooks(hooks))
+	go (&reloader{live: live}).watch()
 	go func() {
-		if err := server.ServeStdio(mcp); err != nil {
-			log.Fatalf("STDIO error: %v", err)
+		if err := server.ServeStdio(mcpSrv); err != nil {
+			fatal("STDIO error", "error", err)
 		}
 	}()
 	<-sigChan
-	log.Println("Received shutdown signal. Exiting STDIO mode.")
+	slog.Info("Received shutdown signal. Exiting STDIO mode.")
 }
 
-func createMCPServer(cfg *config.APIConfig, mode string) *server.MCPServer {
-	mcp := server.NewMCPServer("Appwrite", "0.9.3",
+// fatal logs msg at error level and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// applyLimits installs the rate limits of cfg, if it sets any.
+func applyLimits(cfg *config.APIConfig) {
+	if cfg.ReadRate.Count == 0 && cfg.WriteRate.Count == 0 {
+		return
+	}
+	limit.SetDefault(limit.New(cfg.ReadRate, cfg.WriteRate))
+	slog.Info("Rate limiting tool calls per client", "reads", cfg.ReadRate.String(), "writes", cfg.WriteRate.String())
+}
+
+// requireTokens returns a wrapper that makes a handler require one of
+// tokens, or leaves it open when there are none.
+func requireTokens(tokens *auth.Tokens) func(http.Handler) http.Handler {
+	if tokens.Len() == 0 {
+		return func(h http.Handler) http.Handler { return h }
+	}
+	return func(h http.Handler) http.Handler { return auth.Require(tokens, h) }
+}
+
+// handleMetrics serves /metrics on the MCP port. Metrics reveal tool names
+// and traffic, so they need a token like the MCP endpoint; METRICS_PORT
+// serves them on their own port without one instead.
+func handleMetrics(mux *http.ServeMux, cfg *config.APIConfig, protect func(http.Handler) http.Handler) {
+	if cfg.MetricsPort == "" {
+		mux.Handle("/metrics", protect(metrics.Handler()))
+	}
+}
+
+// serveMetrics serves /metrics on its own port until the process exits.
+func serveMetrics(port string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	addr := net.JoinHostPort("0.0.0.0", port)
+	slog.Info("Serving metrics", "addr", addr, "path", "/metrics")
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		fatal("Metrics server error", "error", err)
+	}
+}
+
+// loadTools returns the function that builds the tool list for a config: the
+// built-in tools, or tools built from SPEC_FILE when it is set. The spec is
+// parsed once here and shared by every server created afterwards.
+func loadTools(cfg *config.APIConfig) (func(*config.APIConfig) []models.Tool, error) {
+	if cfg.SpecFile == "" {
+		return GetAll, nil
+	}
+	spec, err := openapi.Load(cfg.SpecFile)
+	if err != nil {
+		return nil, err
+	}
+	_, skipped := openapi.Tools(spec, cfg, GetAll(cfg))
+	for _, op := range skipped {
+		slog.Warn("No tool for operation: unsupported request body", "operation", op)
+	}
+	slog.Info("Building tools from spec file", "file", cfg.SpecFile, "title", spec.Info.Title, "version", spec.Info.Version)
+	return func(cfg *config.APIConfig) []models.Tool {
+		tools, _ := openapi.Tools(spec, cfg, GetAll(cfg))
+		return tools
+	}, nil
+}
+
+func createMCPServer(cfg *config.APIConfig, mode string, getTools func(*config.APIConfig) []models.Tool, opts ...server.ServerOption) (*server.MCPServer, *liveTools) {
+	live := &liveTools{}
+	opts = append([]server.ServerOption{
 		server.WithToolCapabilities(true),
 		server.WithRecovery(),
-	)
+		// The config of an HTTP request may narrow the tool set further
+		server.WithToolFilter(live.filter),
+	}, opts...)
+	live.mcpSrv = server.NewMCPServer("Appwrite", "0.9.3", opts...)
+	total, _, _, _ := live.update(cfg, getTools)
+	slog.Info("Loaded tools", "count", total, "mode", mode)
+
+	return live.mcpSrv, live
+}
+
+// selectProfile applies the profile a call selects, as withProfile does. A
+// call that cannot use the profile keeps its context and carries the reason
+// instead, for guard to refuse it; profiles are selected before the
+// instrumentation, so logs and the audit log name them, and refusals happen
+// inside it, so they are counted, traced, logged and audited.
+func selectProfile(ctx context.Context, request mcp.CallToolRequest, base *config.APIConfig) (context.Context, mcp.CallToolRequest) {
+	selected, request, _, err := withProfile(ctx, request, base)
+	if err != nil {
+		return context.WithValue(ctx, refusalKey{}, err), request
+	}
+	return selected, request
+}
 
-	tools := GetAll(cfg)
-	log.Printf("Loaded %d tools for %s mode", len(tools), mode)
+type refusalKey struct{}
 
+// guard refuses calls selectProfile rejected and calls to a tool the config
+// of the request hides, as the tool filter only affects tools/list.
+func guard(tool models.Tool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err, ok := ctx.Value(refusalKey{}).(error); ok {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if reqCfg := config.FromContext(ctx, nil); reqCfg != nil && !allowed(tool, reqCfg.Tools) {
+			if reqCfg.Profile != "" {
+				return mcp.NewToolResultError(fmt.Sprintf("Tool %s is not enabled for profile %s", tool.Definition.Name, reqCfg.Profile)), nil
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("Tool %s is not enabled for this session", tool.Definition.Name)), nil
+		}
+		return tool.Handler(ctx, request)
+	}
+}
+
+// filterTools keeps the tools that pass every filter.
+func filterTools(tools []models.Tool, filters []config.ToolFilter) []models.Tool {
+	var kept []models.Tool
 	for _, tool := range tools {
-		mcp.AddTool(tool.Definition, tool.Handler)
+		if allowed(tool, filters) {
+			kept = append(kept, tool)
+		}
 	}
+	return kept
+}
 
-	return mcp
-}
\ No newline at end of file
+func allowed(tool models.Tool, filters []config.ToolFilter) bool {
+	readOnly := tool.Definition.Annotations.ReadOnlyHint != nil && *tool.Definition.Annotations.ReadOnlyHint
+	for _, f := range filters {
+		if !f.Allows(tool.Definition.Name, tool.Service, readOnly) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/egress"
+	"github.com/appwrite/mcp-server/metrics"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func testTool(name, service string, readOnly bool, calls *int) models.Tool {
+	return models.Tool{
+		Definition: mcp.NewTool(name, mcp.WithReadOnlyHintAnnotation(readOnly)),
+		Service:    service,
+		Handler: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			*calls++
+			return mcp.NewToolResultText("{}"), nil
+		},
+	}
+}
+
+func TestRequestFilterOnlyNarrows(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+	up := &upstreams{egress: &egress.Policy{AllowNetwork: []*net.IPNet{loopback}}}
+	env := config.ToolFilter{Services: []string{"users", "teams"}, Deny: []string{"delete_*"}}
+	up.set(&config.APIConfig{Tools: []config.ToolFilter{env}}, nil)
+
+	var calls int
+	getUsers := testTool("get_users", "users", true, &calls)
+	postUsers := testTool("post_users", "users", false, &calls)
+	getTeams := testTool("get_teams", "teams", true, &calls)
+	deleteUser := testTool("delete_users_userId", "users", false, &calls)
+	getBuckets := testTool("get_storage_buckets", "storage", true, &calls)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		allowed []models.Tool
+		refused []models.Tool
+	}{
+		{
+			name:    "environment filter",
+			allowed: []models.Tool{getUsers, postUsers, getTeams},
+			refused: []models.Tool{deleteUser, getBuckets},
+		},
+		{
+			name:    "request narrows",
+			headers: map[string]string{"TOOLS_SERVICES": "users", "READ_ONLY": "true"},
+			allowed: []models.Tool{getUsers},
+			refused: []models.Tool{postUsers, getTeams, deleteUser, getBuckets},
+		},
+		{
+			name:    "request cannot widen",
+			headers: map[string]string{"TOOLS_ALLOW": "*", "TOOLS_SERVICES": "users,storage"},
+			allowed: []models.Tool{getUsers, postUsers},
+			refused: []models.Tool{getTeams, deleteUser, getBuckets},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/mcp", nil)
+			req.Header.Set("API_BASE_URL", "http://127.0.0.1:8080/v1")
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			cfg, err := up.config(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := config.WithContext(context.Background(), cfg)
+			for _, tool := range tt.allowed {
+				calls = 0
+				result, err := guard(tool)(ctx, mcp.CallToolRequest{})
+				if err != nil || result.IsError || calls != 1 {
+					t.Errorf("%s refused: %v, %s", tool.Definition.Name, err, models.ResultText(result))
+				}
+			}
+			for _, tool := range tt.refused {
+				calls = 0
+				result, err := guard(tool)(ctx, mcp.CallToolRequest{})
+				if err != nil || !result.IsError || calls != 0 {
+					t.Errorf("%s allowed: %v, %s", tool.Definition.Name, err, models.ResultText(result))
+				}
+			}
+			if got, want := len(filterTools(append(tt.allowed, tt.refused...), cfg.Tools)), len(tt.allowed); got != want {
+				t.Errorf("tools/list keeps %d tools, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestGuardMessage(t *testing.T) {
+	var calls int
+	tool := testTool("post_users", "users", false, &calls)
+	readOnly := []config.ToolFilter{{ReadOnly: true}}
+
+	tests := []struct {
+		name   string
+		reqCfg *config.APIConfig
+		want   string
+	}{
+		{"session", &config.APIConfig{Tools: readOnly}, "Tool post_users is not enabled for this session"},
+		{"profile", &config.APIConfig{Profile: "prod", Tools: readOnly}, "Tool post_users is not enabled for profile prod"},
+	}
+	for _, tt := range tests {
+		result, err := guard(tool)(config.WithContext(context.Background(), tt.reqCfg), mcp.CallToolRequest{})
+		if err != nil || !result.IsError || models.ResultText(result) != tt.want {
+			t.Errorf("%s: result %q, err %v", tt.name, models.ResultText(result), err)
+		}
+	}
+
+	// Without a request config only the filters applied to tools/list count
+	result, err := guard(tool)(context.Background(), mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Errorf("no request config: result %q, err %v", models.ResultText(result), err)
+	}
+	if calls != 1 {
+		t.Errorf("tool called %d times, want 1", calls)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	tokens := &auth.Tokens{}
+	if err := tokens.Add(auth.Identity{Label: "ci"}, "metrics-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cfg    *config.APIConfig
+		tokens *auth.Tokens
+		token  string
+		want   int
+	}{
+		{"no token", &config.APIConfig{}, tokens, "", http.StatusUnauthorized},
+		{"wrong token", &config.APIConfig{}, tokens, "other-token", http.StatusUnauthorized},
+		{"token", &config.APIConfig{}, tokens, "metrics-token", http.StatusOK},
+		{"authentication disabled", &config.APIConfig{}, &auth.Tokens{}, "", http.StatusOK},
+		{"served on METRICS_PORT", &config.APIConfig{MetricsPort: "9464"}, tokens, "metrics-token", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			handleMetrics(mux, tt.cfg, requireTokens(tt.tokens))
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+// activeSessions scrapes the open session gauge.
+func activeSessions(t *testing.T) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	m := regexp.MustCompile(`(?m)^appwrite_mcp_sessions_active (\d+)$`).FindSubmatch(body)
+	if m == nil {
+		t.Fatal("no appwrite_mcp_sessions_active sample")
+	}
+	n, _ := strconv.Atoi(string(m[1]))
+	return n
+}
+
+func TestHTTPSessionsAreCounted(t *testing.T) {
+	h, _ := sessionServer(0)
+	before := activeSessions(t)
+	id := initialize(t, h, "http://127.0.0.1:8080/v1")
+	if got := activeSessions(t); got != before+1 {
+		t.Errorf("sessions = %d, want %d", got, before+1)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, mcpRequest(http.MethodDelete, id, ""))
+	if got := activeSessions(t); got != before {
+		t.Errorf("sessions after DELETE = %d, want %d", got, before)
+	}
+}
@@ -0,0 +1,134 @@
+// Package metrics exposes Prometheus metrics for tool calls, upstream
+// Appwrite requests and MCP sessions. Tool calls are counted by Instrument,
+// which wraps every tool handler; the upstream client reports each request
+// through Upstream, attributing it to the tool call in its context.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// noStatus labels errors and upstream requests that got no HTTP response.
+const noStatus = "none"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	toolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "appwrite_mcp_tool_calls_total",
+		Help: "Tool calls received, by tool.",
+	}, []string{"tool"})
+	toolErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "appwrite_mcp_tool_errors_total",
+		Help: "Tool calls that failed, by tool and the HTTP status of the last Appwrite response (none if there was no response).",
+	}, []string{"tool", "status"})
+	toolsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "appwrite_mcp_tool_calls_in_flight",
+		Help: "Tool calls being handled, by tool.",
+	}, []string{"tool"})
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "appwrite_mcp_upstream_request_duration_seconds",
+		Help:    "Latency of Appwrite requests until response headers, by tool, method and HTTP status.",
+		Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"tool", "method", "status"})
+	upstreamInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "appwrite_mcp_upstream_requests_in_flight",
+		Help: "Appwrite requests waiting for a response.",
+	})
+	activeSessions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "appwrite_mcp_sessions_active",
+		Help: "Open MCP sessions.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		toolCalls, toolErrors, toolsInFlight,
+		upstreamDuration, upstreamInFlight, activeSessions,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+type contextKey struct{}
+
+// call is the state of one tool call that upstream requests report to.
+type call struct {
+	tool   string
+	status atomic.Int64 // HTTP status of the last upstream response, 0 if none
+}
+
+// Instrument wraps the handler of tool so every call is counted, along with
+// its outcome and the upstream requests it makes.
+func Instrument(tool models.Tool) models.Tool {
+	name := tool.Definition.Name
+	next := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		toolCalls.WithLabelValues(name).Inc()
+		inFlight := toolsInFlight.WithLabelValues(name)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c := &call{tool: name}
+		result, err := next(context.WithValue(ctx, contextKey{}, c), request)
+		if err != nil || (result != nil && result.IsError) {
+			status := noStatus
+			if code := c.status.Load(); code != 0 {
+				status = strconv.FormatInt(code, 10)
+			}
+			toolErrors.WithLabelValues(name, status).Inc()
+		}
+		return result, err
+	}
+	return tool
+}
+
+// Upstream records the start of an Appwrite request made on behalf of the
+// tool call in ctx. The returned function must be called with the response
+// status, or 0 if the request failed without one.
+func Upstream(ctx context.Context, method string) func(status int) {
+	c, _ := ctx.Value(contextKey{}).(*call)
+	start := time.Now()
+	upstreamInFlight.Inc()
+	return func(status int) {
+		upstreamInFlight.Dec()
+		tool, label := "", noStatus
+		if c != nil {
+			tool = c.tool
+			c.status.Store(int64(status))
+		}
+		if status != 0 {
+			label = strconv.Itoa(status)
+		}
+		upstreamDuration.WithLabelValues(tool, method, label).Observe(time.Since(start).Seconds())
+	}
+}
+
+// SessionStarted and SessionEnded track the number of open MCP sessions.
+func SessionStarted() { activeSessions.Inc() }
+
+// SessionEnded is the counterpart of SessionStarted.
+func SessionEnded() { activeSessions.Dec() }
+
+// AddSessionHooks counts the sessions the MCP server registers, which are
+// the STDIO session and SSE connections.
+func AddSessionHooks(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(context.Context, server.ClientSession) { SessionStarted() })
+	hooks.AddOnUnregisterSession(func(context.Context, server.ClientSession) { SessionEnded() })
+}
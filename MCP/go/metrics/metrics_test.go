@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// value scrapes Handler for the sample of metric with exactly the given
+// labels, e.g. {tool="get_users"}, or 0 if there is none.
+func value(t *testing.T, metric, labels string) float64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	m := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(metric+labels) + ` (\S+)$`).FindSubmatch(body)
+	if m == nil {
+		return 0
+	}
+	v, err := strconv.ParseFloat(string(m[1]), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func tool(name string, handler func(ctx context.Context) (*mcp.CallToolResult, error)) models.Tool {
+	return Instrument(models.Tool{
+		Definition: mcp.NewTool(name),
+		Handler: func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handler(ctx)
+		},
+	})
+}
+
+func TestInstrument(t *testing.T) {
+	var inFlight float64
+	tests := []struct {
+		name    string
+		handler func(ctx context.Context) (*mcp.CallToolResult, error)
+		status  string // Label of the error counter, "" for a success
+	}{
+		{"metrics_ok", func(ctx context.Context) (*mcp.CallToolResult, error) {
+			inFlight = value(t, "appwrite_mcp_tool_calls_in_flight", `{tool="metrics_ok"}`)
+			Upstream(ctx, "GET")(200)
+			return mcp.NewToolResultText("{}"), nil
+		}, ""},
+		{"metrics_upstream_error", func(ctx context.Context) (*mcp.CallToolResult, error) {
+			Upstream(ctx, "GET")(500)
+			Upstream(ctx, "POST")(404)
+			return mcp.NewToolResultError("Not found"), nil
+		}, "404"},
+		{"metrics_no_response", func(ctx context.Context) (*mcp.CallToolResult, error) {
+			Upstream(ctx, "GET")(0)
+			return nil, errors.New("connection refused")
+		}, "none"},
+		{"metrics_refused", func(context.Context) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("Tool is not enabled"), nil
+		}, "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool(tt.name, tt.handler).Handler(context.Background(), mcp.CallToolRequest{})
+			if got := value(t, "appwrite_mcp_tool_calls_total", `{tool="`+tt.name+`"}`); got != 1 {
+				t.Errorf("calls = %v, want 1", got)
+			}
+			if got := value(t, "appwrite_mcp_tool_calls_in_flight", `{tool="`+tt.name+`"}`); got != 0 {
+				t.Errorf("in flight after the call = %v", got)
+			}
+			for _, status := range []string{"none", "200", "404", "500"} {
+				want := 0.0
+				if status == tt.status {
+					want = 1
+				}
+				if got := value(t, "appwrite_mcp_tool_errors_total", `{status="`+status+`",tool="`+tt.name+`"}`); got != want {
+					t.Errorf("errors with status %s = %v, want %v", status, got, want)
+				}
+			}
+		})
+	}
+	if inFlight != 1 {
+		t.Errorf("in flight during the call = %v, want 1", inFlight)
+	}
+	for _, sample := range []struct {
+		labels string
+		want   float64
+	}{
+		{`{method="GET",status="200",tool="metrics_ok"}`, 1},
+		{`{method="GET",status="500",tool="metrics_upstream_error"}`, 1},
+		{`{method="POST",status="404",tool="metrics_upstream_error"}`, 1},
+		{`{method="GET",status="none",tool="metrics_no_response"}`, 1},
+	} {
+		if got := value(t, "appwrite_mcp_upstream_request_duration_seconds_count", sample.labels); got != sample.want {
+			t.Errorf("upstream requests %s = %v, want %v", sample.labels, got, sample.want)
+		}
+	}
+	if got := value(t, "appwrite_mcp_upstream_requests_in_flight", ""); got != 0 {
+		t.Errorf("upstream requests in flight = %v", got)
+	}
+
+	// Requests made outside a tool call are counted without a tool
+	before := value(t, "appwrite_mcp_upstream_request_duration_seconds_count", `{method="GET",status="200",tool=""}`)
+	Upstream(context.Background(), "GET")(200)
+	if got := value(t, "appwrite_mcp_upstream_request_duration_seconds_count", `{method="GET",status="200",tool=""}`); got != before+1 {
+		t.Errorf("upstream requests without a tool = %v, want %v", got, before+1)
+	}
+}
+
+type fakeSession struct{ id string }
+
+func (s fakeSession) Initialize()                                         {}
+func (s fakeSession) Initialized() bool                                   { return true }
+func (s fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s fakeSession) SessionID() string                                   { return s.id }
+
+func TestSessionHooks(t *testing.T) {
+	hooks := &server.Hooks{}
+	AddSessionHooks(hooks)
+	mcpSrv := server.NewMCPServer("test", "0", server.WithHooks(hooks))
+	ctx := context.Background()
+
+	before := value(t, "appwrite_mcp_sessions_active", "")
+	mcpSrv.RegisterSession(ctx, fakeSession{"a"})
+	mcpSrv.RegisterSession(ctx, fakeSession{"b"})
+	if got := value(t, "appwrite_mcp_sessions_active", ""); got != before+2 {
+		t.Errorf("sessions = %v, want %v", got, before+2)
+	}
+	mcpSrv.UnregisterSession(ctx, "a")
+	mcpSrv.UnregisterSession(ctx, "a")
+	if got := value(t, "appwrite_mcp_sessions_active", ""); got != before+1 {
+		t.Errorf("sessions after closing one = %v, want %v", got, before+1)
+	}
+	mcpSrv.UnregisterSession(ctx, "b")
+	if got := value(t, "appwrite_mcp_sessions_active", ""); got != before {
+		t.Errorf("sessions after closing all = %v, want %v", got, before)
+	}
+}
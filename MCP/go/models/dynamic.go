@@ -0,0 +1,124 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalJSON keeps every attribute of the document. Attributes that are not
+// part of the Document schema end up in Data.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	type document Document
+	var known document
+	extra, err := unmarshalWithExtra(data, &known)
+	if err != nil {
+		return err
+	}
+	known.Data = extra
+	*d = Document(known)
+	return nil
+}
+
+// MarshalJSON writes the document attributes from Data next to the schema
+// fields, mirroring the shape Appwrite returns.
+func (d Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	return marshalWithExtra(document(d), d.Data)
+}
+
+// UnmarshalJSON accepts both objects and the empty array Appwrite returns for
+// users without preferences.
+func (p *Preferences) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); bytes.Equal(trimmed, []byte("[]")) {
+		*p = Preferences{}
+		return nil
+	}
+	var values map[string]interface{}
+	if err := decodeNumbers(data, &values); err != nil {
+		return err
+	}
+	*p = values
+	return nil
+}
+
+// decodeNumbers unmarshals data keeping numbers as json.Number, so large
+// integers and decimals survive a round-trip unchanged.
+func decodeNumbers(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// unmarshalWithExtra decodes data into known and returns all properties that
+// do not map onto one of its JSON fields.
+func unmarshalWithExtra(data []byte, known interface{}) (map[string]interface{}, error) {
+	if err := json.Unmarshal(data, known); err != nil {
+		return nil, err
+	}
+	var all map[string]interface{}
+	if err := decodeNumbers(data, &all); err != nil {
+		return nil, err
+	}
+	for _, name := range jsonFields(reflect.TypeOf(known).Elem()) {
+		delete(all, name)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// marshalWithExtra encodes known and merges extra into the resulting object.
+// Schema fields win over extra properties with the same name.
+func marshalWithExtra(known interface{}, extra map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(known)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var merged map[string]interface{}
+	if err := decodeNumbers(data, &merged); err != nil {
+		return nil, err
+	}
+	for key, val := range extra {
+		if _, ok := merged[key]; !ok {
+			merged[key] = val
+		}
+	}
+	return json.Marshal(merged)
+}
+
+func jsonFields(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// UnmarshalJSON accepts the error code as a number, which is how Appwrite
+// actually sends it, as well as the string the schema declares.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Version string          `json:"version"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var code string
+	if len(raw.Code) > 0 && !bytes.Equal(raw.Code, []byte("null")) {
+		// A number is kept as its literal digits
+		if err := json.Unmarshal(raw.Code, &code); err != nil {
+			code = string(raw.Code)
+		}
+	}
+	*e = Error{Code: code, Message: raw.Message, Version: raw.Version}
+	return nil
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// responseModels lists the Go type for every schema in openapi.yaml.
+var responseModels = map[string]func() interface{}{
+	"collection":     func() interface{} { return new(Collection) },
+	"collectionList": func() interface{} { return new(CollectionList) },
+	"continent":      func() interface{} { return new(Continent) },
+	"continentList":  func() interface{} { return new(ContinentList) },
+	"country":        func() interface{} { return new(Country) },
+	"countryList":    func() interface{} { return new(CountryList) },
+	"currency":       func() interface{} { return new(Currency) },
+	"currencyList":   func() interface{} { return new(CurrencyList) },
+	"document":       func() interface{} { return new(Document) },
+	"documentList":   func() interface{} { return new(DocumentList) },
+	"error":          func() interface{} { return new(Error) },
+	"execution":      func() interface{} { return new(Execution) },
+	"executionList":  func() interface{} { return new(ExecutionList) },
+	"file":           func() interface{} { return new(File) },
+	"fileList":       func() interface{} { return new(FileList) },
+	"function":       func() interface{} { return new(Function) },
+	"functionList":   func() interface{} { return new(FunctionList) },
+	"language":       func() interface{} { return new(Language) },
+	"languageList":   func() interface{} { return new(LanguageList) },
+	"locale":         func() interface{} { return new(Locale) },
+	"log":            func() interface{} { return new(Log) },
+	"logList":        func() interface{} { return new(LogList) },
+	"membership":     func() interface{} { return new(Membership) },
+	"membershipList": func() interface{} { return new(MembershipList) },
+	"permissions":    func() interface{} { return new(Permissions) },
+	"phone":          func() interface{} { return new(Phone) },
+	"phoneList":      func() interface{} { return new(PhoneList) },
+	"preferences":    func() interface{} { return new(Preferences) },
+	"rule":           func() interface{} { return new(Rule) },
+	"session":        func() interface{} { return new(Session) },
+	"sessionList":    func() interface{} { return new(SessionList) },
+	"tag":            func() interface{} { return new(Tag) },
+	"tagList":        func() interface{} { return new(TagList) },
+	"team":           func() interface{} { return new(Team) },
+	"teamList":       func() interface{} { return new(TeamList) },
+	"token":          func() interface{} { return new(Token) },
+	"user":           func() interface{} { return new(User) },
+	"userList":       func() interface{} { return new(UserList) },
+}
+
+// dynamicProperties are added to schemas declaring additionalProperties, the
+// way user defined document attributes and preference keys show up upstream.
+var dynamicProperties = map[string]interface{}{
+	"title":    "Avatar",
+	"year":     json.Number("9007199254740993"),
+	"rating":   json.Number("8.123456789012345"),
+	"released": true,
+	"cast":     []interface{}{"Sam Worthington", "Zoe Saldana"},
+	"studio":   map[string]interface{}{"$id": "5e5ea5c16897e", "name": "20th Century Fox"},
+	"sequel":   nil,
+}
+
+type schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Properties           map[string]*schema `yaml:"properties"`
+	Items                *schema            `yaml:"items"`
+	AdditionalProperties interface{}        `yaml:"additionalProperties"`
+}
+
+func loadSchemas(t *testing.T) map[string]*schema {
+	t.Helper()
+	data, err := os.ReadFile("../../../openapi.yaml")
+	if err != nil {
+		t.Fatalf("read spec: %v", err)
+	}
+	var spec struct {
+		Components struct {
+			Schemas map[string]*schema `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("parse spec: %v", err)
+	}
+	return spec.Components.Schemas
+}
+
+// sample builds a response with every property of s populated with a non-zero
+// value, so a field dropped or truncated by the Go type shows up as a diff.
+func sample(s *schema, schemas map[string]*schema) interface{} {
+	if s.Ref != "" {
+		return sample(schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")], schemas)
+	}
+	switch s.Type {
+	case "string":
+		return "value"
+	case "integer":
+		return json.Number("1592981250")
+	case "number":
+		return json.Number("0.123456789012345")
+	case "boolean":
+		return true
+	case "array":
+		return []interface{}{sample(s.Items, schemas), sample(s.Items, schemas)}
+	}
+	obj := map[string]interface{}{}
+	if len(s.Properties) == 0 && s.Items != nil {
+		// Objects described through items (prefs, $permissions) are key-value maps
+		obj["key"] = sample(s.Items, schemas)
+		return obj
+	}
+	for name, prop := range s.Properties {
+		obj[name] = sample(prop, schemas)
+	}
+	if s.AdditionalProperties == true {
+		for name, val := range dynamicProperties {
+			obj[name] = val
+		}
+	}
+	return obj
+}
+
+func decode(t *testing.T, data []byte) interface{} {
+	t.Helper()
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return v
+}
+
+func TestModelsRoundTripWithoutLoss(t *testing.T) {
+	schemas := loadSchemas(t)
+	for name := range responseModels {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("model for %q has no schema in openapi.yaml", name)
+		}
+	}
+	for name, s := range schemas {
+		t.Run(name, func(t *testing.T) {
+			newModel, ok := responseModels[name]
+			if !ok {
+				t.Fatalf("schema %q has no model", name)
+			}
+			upstream, err := json.Marshal(sample(s, schemas))
+			if err != nil {
+				t.Fatal(err)
+			}
+			model := newModel()
+			if err := json.Unmarshal(upstream, model); err != nil {
+				t.Fatalf("unmarshal %s: %v", upstream, err)
+			}
+			out, err := json.MarshalIndent(model, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := decode(t, upstream), decode(t, out); !reflect.DeepEqual(want, got) {
+				t.Errorf("round-trip changed the response\nupstream: %s\nmodel:    %s", upstream, out)
+			}
+		})
+	}
+}
+
+func TestPreferencesAcceptEmptyArray(t *testing.T) {
+	var user User
+	if err := json.Unmarshal([]byte(`{"$id":"1","prefs":[]}`), &user); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if user.Prefs == nil || len(user.Prefs) != 0 {
+		t.Errorf("expected empty preferences, got %#v", user.Prefs)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	for body, want := range map[string]string{
+		`{"message":"m","code":404}`:                "404",
+		`{"message":"m","code":"user_not_found"}`:   "user_not_found",
+		`{"message":"m","code":null}`:               "",
+		`{"message":"m"}`:                           "",
+		`{"message":"m","code":true,"version":"1"}`: "true",
+	} {
+		var e Error
+		if err := json.Unmarshal([]byte(body), &e); err != nil {
+			t.Fatalf("%s: %v", body, err)
+		}
+		if e.Code != want || e.Message != "m" {
+			t.Errorf("%s: code = %q, message = %q, want code %q", body, e.Code, e.Message, want)
+		}
+	}
+}
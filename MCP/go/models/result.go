@@ -0,0 +1,14 @@
+package models
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+// ResultText returns the first text content of a tool result, which is the
+// JSON body or error message of the tools in this server, or "".
+func ResultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
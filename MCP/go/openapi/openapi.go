@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the subset of an OpenAPI 3 document needed to build MCP tools.
+type Spec struct {
+	Info       Info                             `yaml:"info"`
+	Paths      map[string]map[string]*Operation `yaml:"paths"`
+	Components Components                       `yaml:"components"`
+}
+
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `yaml:"type"`
+	In          string `yaml:"in"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+// Operation is a single method on a path. Method and Path are filled in by
+// Parse.
+type Operation struct {
+	OperationID string                `yaml:"operationId"`
+	Summary     string                `yaml:"summary"`
+	Description string                `yaml:"description"`
+	Tags        []string              `yaml:"tags"`
+	Parameters  []*Parameter          `yaml:"parameters"`
+	RequestBody *RequestBody          `yaml:"requestBody"`
+	Responses   map[string]*Response  `yaml:"responses"`
+	Security    []map[string][]string `yaml:"security"`
+	Appwrite    Appwrite              `yaml:"x-appwrite"`
+
+	Method string `yaml:"-"`
+	Path   string `yaml:"-"`
+}
+
+// Appwrite holds the x-appwrite vendor extension of an operation.
+type Appwrite struct {
+	Scope string `yaml:"scope"`
+	Type  string `yaml:"type"`
+}
+
+type Parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Description          string             `yaml:"description"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Items                *Schema            `yaml:"items"`
+	Required             []string           `yaml:"required"`
+	AdditionalProperties any                `yaml:"additionalProperties"`
+	Default              any                `yaml:"default"`
+	Enum                 []any              `yaml:"enum"`
+}
+
+// methodOrder is the order operations on the same path are listed in.
+var methodOrder = map[string]int{"get": 0, "post": 1, "put": 2, "patch": 3, "delete": 4}
+
+// Load reads and parses the spec at path.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses an OpenAPI 3 document in YAML or JSON form.
+func Parse(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("parse spec: no paths defined")
+	}
+	for path, ops := range spec.Paths {
+		for method, op := range ops {
+			if _, ok := methodOrder[method]; !ok || op == nil {
+				delete(ops, method)
+				continue
+			}
+			op.Method = strings.ToUpper(method)
+			op.Path = path
+		}
+	}
+	return &spec, nil
+}
+
+// Operations returns every operation sorted by path and method, so output
+// derived from it is stable.
+func (s *Spec) Operations() []*Operation {
+	var ops []*Operation
+	for _, byMethod := range s.Paths {
+		for _, op := range byMethod {
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return methodOrder[strings.ToLower(ops[i].Method)] < methodOrder[strings.ToLower(ops[j].Method)]
+	})
+	return ops
+}
+
+// Resolve follows a local $ref to its component schema.
+func (s *Spec) Resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.Components.Schemas[RefName(schema.Ref)]
+	}
+	return schema
+}
+
+// RefName returns the component name a $ref points to.
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// SortedKeys returns the keys of m in sorted order.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
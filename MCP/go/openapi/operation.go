@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Param is an operation input as exposed on an MCP tool: a path, query,
+// JSON body or multipart form parameter.
+type Param struct {
+	Name        string
+	In          string // path, query, body or form
+	Type        string // string, number, boolean, array or object
+	ItemsType   string // Element type for arrays, if known
+	Description string
+	Required    bool
+	File        bool // Form field carrying file content, passed as a path below FILE_ROOT
+}
+
+// ToolName derives the MCP tool name from the method and path, e.g.
+// get_database_collections_collectionId_documents.
+func (o *Operation) ToolName() string {
+	parts := []string{strings.ToLower(o.Method)}
+	for _, seg := range strings.Split(strings.Trim(o.Path, "/"), "/") {
+		parts = append(parts, strings.Trim(seg, "{}"))
+	}
+	return strings.Join(parts, "_")
+}
+
+// Service is the first tag of the operation, which Appwrite uses to group
+// operations by service.
+func (o *Operation) Service() string {
+	if len(o.Tags) == 0 {
+		return "default"
+	}
+	return o.Tags[0]
+}
+
+// SecuritySchemes flattens the security requirement into a sorted list of
+// scheme names.
+func (o *Operation) SecuritySchemes() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, req := range o.Security {
+		for name := range req {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BodyContentType returns the media type of the request body, or "" if the
+// operation takes none.
+func (o *Operation) BodyContentType() string {
+	if o.RequestBody == nil {
+		return ""
+	}
+	for _, ct := range []string{"application/json", "multipart/form-data"} {
+		if _, ok := o.RequestBody.Content[ct]; ok {
+			return ct
+		}
+	}
+	keys := SortedKeys(o.RequestBody.Content)
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// SuccessResponse returns the first 2xx response.
+func (o *Operation) SuccessResponse() *Response {
+	for _, code := range SortedKeys(o.Responses) {
+		if strings.HasPrefix(code, "2") {
+			return o.Responses[code]
+		}
+	}
+	return nil
+}
+
+// ResponseModel returns the component schema name of the JSON success
+// response, or "" if it has none.
+func (o *Operation) ResponseModel() string {
+	resp := o.SuccessResponse()
+	if resp == nil {
+		return ""
+	}
+	if mt, ok := resp.Content["application/json"]; ok && mt.Schema != nil && mt.Schema.Ref != "" {
+		return RefName(mt.Schema.Ref)
+	}
+	return ""
+}
+
+// Binary reports whether the operation returns file content instead of JSON.
+// Appwrite marks these with x-appwrite.type "location" and documents no
+// response content.
+func (o *Operation) Binary() bool {
+	if o.Appwrite.Type == "location" {
+		return true
+	}
+	resp := o.SuccessResponse()
+	return o.Method == "GET" && resp != nil && len(resp.Content) == 0 && o.ResponseModel() == ""
+}
+
+// Annotations derives the MCP tool hints from the HTTP method and the
+// operation: GET is read-only, POST only adds, and PUT, PATCH and DELETE
+// change or remove existing data. Everything except POST is idempotent,
+// unless the operation consumes a one-time secret. Operations that take a url
+// reach outside Appwrite, fetching it or mailing it to a user, as do function
+// executions.
+func (o *Operation) Annotations(spec *Spec) mcp.ToolAnnotation {
+	readOnly := o.Method == "GET" || o.Method == "HEAD"
+	destructive := o.Method == "PUT" || o.Method == "PATCH" || o.Method == "DELETE"
+	idempotent := o.Method != "POST"
+	openWorld := o.Appwrite.Scope == "execution.write"
+	for _, p := range o.Params(spec) {
+		switch {
+		case p.Name == "url":
+			openWorld = true
+		case p.Name == "secret" && !readOnly:
+			idempotent = false
+		}
+	}
+	return mcp.ToolAnnotation{
+		Title:           o.Summary,
+		ReadOnlyHint:    mcp.ToBoolPtr(readOnly),
+		DestructiveHint: mcp.ToBoolPtr(destructive),
+		IdempotentHint:  mcp.ToBoolPtr(idempotent),
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),
+	}
+}
+
+// Params lists the tool parameters: path parameters first, then query
+// parameters in spec order, then JSON body or multipart form properties
+// sorted by name.
+func (o *Operation) Params(spec *Spec) []Param {
+	var params []Param
+	for _, in := range []string{"path", "query"} {
+		for _, p := range o.Parameters {
+			if p.In != in {
+				continue
+			}
+			param := Param{Name: p.Name, In: in, Description: p.Description, Required: p.Required || in == "path"}
+			param.Type, param.ItemsType = paramType(spec.Resolve(p.Schema), spec)
+			params = append(params, param)
+		}
+	}
+	ct := o.BodyContentType()
+	in := map[string]string{"application/json": "body", "multipart/form-data": "form"}[ct]
+	if in == "" {
+		return params
+	}
+	body := spec.Resolve(o.RequestBody.Content[ct].Schema)
+	if body == nil {
+		return params
+	}
+	required := map[string]bool{}
+	for _, name := range body.Required {
+		required[name] = true
+	}
+	for _, name := range SortedKeys(body.Properties) {
+		prop := spec.Resolve(body.Properties[name])
+		param := Param{Name: name, In: in, Description: prop.Description, Required: required[name]}
+		param.Type, param.ItemsType = paramType(prop, spec)
+		param.File = in == "form" && (prop.Type == "file" || prop.Format == "binary")
+		params = append(params, param)
+	}
+	return params
+}
+
+// Generic reports whether a tool for the operation can be built from the
+// spec alone. Multipart bodies are only supported when the spec marks the
+// file fields with format: binary; Appwrite's own spec does not, so its
+// uploads need hand-written tools.
+func (o *Operation) Generic(spec *Spec) bool {
+	switch o.BodyContentType() {
+	case "", "application/json":
+		return true
+	case "multipart/form-data":
+		for _, p := range o.Params(spec) {
+			if p.File {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParamsIn returns the names of the parameters located in in.
+func ParamsIn(params []Param, in string) []string {
+	var names []string
+	for _, p := range params {
+		if p.In == in {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+func paramType(s *Schema, spec *Spec) (string, string) {
+	if s == nil {
+		return "string", ""
+	}
+	switch s.Type {
+	case "integer", "number":
+		return "number", ""
+	case "boolean":
+		return "boolean", ""
+	case "array":
+		items, _ := paramType(spec.Resolve(s.Items), spec)
+		if s.Items == nil {
+			items = ""
+		}
+		return "array", items
+	case "object":
+		return "object", ""
+	}
+	return "string", ""
+}
@@ -0,0 +1,65 @@
+package openapi
+
+import "testing"
+
+func TestAnnotations(t *testing.T) {
+	spec, err := Parse([]byte(`
+paths:
+  /items/{itemId}:
+    get: {summary: Get Item}
+    post: {summary: Create Item}
+    put: {summary: Replace Item}
+    patch: {summary: Update Item}
+    delete: {summary: Delete Item}
+  /webhooks:
+    post:
+      summary: Create Webhook
+      requestBody: {content: {application/json: {schema: {type: object, properties: {url: {type: string}}}}}}
+  /tokens:
+    put:
+      summary: Confirm Token
+      requestBody: {content: {application/json: {schema: {type: object, properties: {secret: {type: string}}}}}}
+  /functions/{functionId}/executions:
+    post:
+      summary: Create Execution
+      x-appwrite: {scope: execution.write}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method, path                                 string
+		readOnly, destructive, idempotent, openWorld bool
+	}{
+		{"GET", "/items/{itemId}", true, false, true, false},
+		{"POST", "/items/{itemId}", false, false, false, false},
+		{"PUT", "/items/{itemId}", false, true, true, false},
+		{"PATCH", "/items/{itemId}", false, true, true, false},
+		{"DELETE", "/items/{itemId}", false, true, true, false},
+		{"POST", "/webhooks", false, false, false, true},
+		{"PUT", "/tokens", false, true, false, false},
+		{"POST", "/functions/{functionId}/executions", false, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			var op *Operation
+			for _, o := range spec.Operations() {
+				if o.Method == tt.method && o.Path == tt.path {
+					op = o
+				}
+			}
+			if op == nil {
+				t.Fatal("operation not found")
+			}
+			a := op.Annotations(spec)
+			if a.Title != op.Summary {
+				t.Errorf("title = %q, want %q", a.Title, op.Summary)
+			}
+			if *a.ReadOnlyHint != tt.readOnly || *a.DestructiveHint != tt.destructive || *a.IdempotentHint != tt.idempotent || *a.OpenWorldHint != tt.openWorld {
+				t.Errorf("readOnly %v destructive %v idempotent %v openWorld %v, want %v %v %v %v",
+					*a.ReadOnlyHint, *a.DestructiveHint, *a.IdempotentHint, *a.OpenWorldHint,
+					tt.readOnly, tt.destructive, tt.idempotent, tt.openWorld)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/files"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// Tools builds a tool for every operation in spec, served by a generic
+// handler driven by the parsed operation. Operations the generic handler
+// cannot serve use the tool of the same name from builtin, if there is one.
+// The names of operations left without a tool are returned as skipped.
+func Tools(spec *Spec, cfg *config.APIConfig, builtin []models.Tool) (tools []models.Tool, skipped []string) {
+	byName := map[string]models.Tool{}
+	for _, tool := range builtin {
+		byName[tool.Definition.Name] = tool
+	}
+	for _, op := range spec.Operations() {
+		if op.Generic(spec) {
+			tools = append(tools, Tool(spec, op, cfg))
+			if op.Binary() {
+				tools = append(tools, appwrite.SaveTool(tools[len(tools)-1]))
+			}
+			continue
+		}
+		if tool, ok := byName[op.ToolName()]; ok {
+			tools = append(tools, tool)
+			if save, ok := byName[appwrite.SaveTool(tool).Definition.Name]; ok {
+				tools = append(tools, save)
+			}
+			continue
+		}
+		skipped = append(skipped, fmt.Sprintf("%s %s (%s)", op.Method, op.Path, op.OperationID))
+	}
+	return tools, skipped
+}
+
+// Tool builds the MCP tool for a single operation.
+func Tool(spec *Spec, op *Operation, cfg *config.APIConfig) models.Tool {
+	params := op.Params(spec)
+	description := op.Summary
+	if description == "" {
+		description = oneLine(op.Description)
+	}
+	opts := []mcp.ToolOption{
+		mcp.WithDescription(description),
+		mcp.WithToolAnnotation(op.Annotations(spec)),
+	}
+	for _, p := range params {
+		opts = append(opts, toolOption(p))
+	}
+	return models.Tool{
+		Definition: mcp.NewTool(op.ToolName(), opts...),
+		Service:    op.Service(),
+		Handler:    handler(op, params, cfg),
+	}
+}
+
+func toolOption(p Param) mcp.ToolOption {
+	desc := oneLine(p.Description)
+	switch {
+	case p.File:
+		desc = "Path of a local file to upload, relative to FILE_ROOT. " + desc
+	case p.In == "body" || p.In == "form":
+		desc = "Input parameter: " + desc
+	}
+	opts := []mcp.PropertyOption{mcp.Description(desc)}
+	if p.Required {
+		opts = append(opts, mcp.Required())
+	}
+	switch p.Type {
+	case "number":
+		return mcp.WithNumber(p.Name, opts...)
+	case "boolean":
+		return mcp.WithBoolean(p.Name, opts...)
+	case "object":
+		return mcp.WithObject(p.Name, opts...)
+	case "array":
+		if p.ItemsType == "string" {
+			opts = append(opts, mcp.WithStringItems())
+		}
+		return mcp.WithArray(p.Name, opts...)
+	}
+	return mcp.WithString(p.Name, opts...)
+}
+
+func handler(op *Operation, params []Param, cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	security := op.SecuritySchemes()
+	binary := op.Binary()
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok && request.Params.Arguments != nil {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+
+		var missing string
+		path := pathParam.ReplaceAllStringFunc(op.Path, func(m string) string {
+			name := m[1 : len(m)-1]
+			val, ok := args[name].(string)
+			if !ok && missing == "" {
+				missing = name
+			}
+			return url.PathEscape(val)
+		})
+		if missing != "" {
+			if _, ok := args[missing]; !ok {
+				return mcp.NewToolResultError("Missing required path parameter: " + missing), nil
+			}
+			return mcp.NewToolResultError("Invalid path parameter: " + missing), nil
+		}
+
+		r := &appwrite.Request{
+			Method:   op.Method,
+			Path:     path,
+			Query:    appwrite.QueryFromArgs(args, ParamsIn(params, "query")...),
+			Security: security,
+			Scope:    op.Appwrite.Scope,
+		}
+		if names := ParamsIn(params, "body"); len(names) > 0 {
+			body := map[string]any{}
+			for _, name := range names {
+				if val, ok := args[name]; ok {
+					body[name] = val
+				}
+			}
+			r.JSON = body
+		}
+		if len(ParamsIn(params, "form")) > 0 {
+			body, contentType, err := form(params, args, config.FromContext(ctx, cfg).FileRoot)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("Invalid file argument", err), nil
+			}
+			defer body.Close()
+			r.Body, r.ContentType = body, contentType
+		}
+
+		if binary {
+			return appwrite.CallBinary(ctx, cfg, r)
+		}
+		var result any
+		return appwrite.Call(ctx, cfg, r, &result)
+	}
+}
+
+// form encodes the multipart body. File fields name a path below root;
+// everything else is encoded like a query argument.
+func form(params []Param, args map[string]any, root string) (io.ReadCloser, string, error) {
+	var parts []files.Part
+	var opened []io.Closer
+	for _, p := range params {
+		val, ok := args[p.Name]
+		if p.In != "form" || !ok {
+			continue
+		}
+		if !p.File {
+			values := url.Values{}
+			appwrite.AddQuery(values, p.Name, val)
+			for name, vs := range values {
+				for _, v := range vs {
+					parts = append(parts, files.Part{Name: name, Value: v})
+				}
+			}
+			continue
+		}
+		path, ok := val.(string)
+		if !ok {
+			closeAll(opened)
+			return nil, "", fmt.Errorf("%s must be a path", p.Name)
+		}
+		content, filename, err := files.Source{Path: path}.Open(root)
+		if err != nil {
+			closeAll(opened)
+			return nil, "", err
+		}
+		opened = append(opened, content)
+		parts = append(parts, files.Part{Name: p.Name, Filename: filename, Reader: content})
+	}
+	body, contentType := files.Multipart(parts)
+	return closer{body, opened}, contentType, nil
+}
+
+// closer closes the multipart stream together with the files it reads from.
+type closer struct {
+	io.ReadCloser
+	files []io.Closer
+}
+
+func (c closer) Close() error {
+	closeAll(c.files)
+	return c.ReadCloser.Close()
+}
+
+func closeAll(cs []io.Closer) {
+	for _, c := range cs {
+		c.Close()
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// received is a request as seen by the upstream.
+type received struct {
+	method, uri, contentType string
+	body                     []byte
+}
+
+// upstream records every request and answers with an empty JSON object.
+func upstream(t *testing.T) (*httptest.Server, *[]received) {
+	var got []received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, received{r.Method, r.RequestURI, r.Header.Get("Content-Type"), body})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func testTools(t *testing.T, cfg *config.APIConfig) map[string]models.Tool {
+	spec, err := Load("testdata/spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools, skipped := Tools(spec, cfg, nil)
+	if len(skipped) > 0 {
+		t.Fatalf("skipped: %v", skipped)
+	}
+	byName := map[string]models.Tool{}
+	for _, tool := range tools {
+		byName[tool.Definition.Name] = tool
+	}
+	return byName
+}
+
+func callTool(t *testing.T, tool models.Tool, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	var request mcp.CallToolRequest
+	request.Params.Name = tool.Definition.Name
+	request.Params.Arguments = args
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	if len(result.Content) == 0 {
+		return ""
+	}
+	text, _ := result.Content[0].(mcp.TextContent)
+	return text.Text
+}
+
+func TestGenericHandler(t *testing.T) {
+	srv, got := upstream(t)
+	appwrite.SetDefault(appwrite.NewClient(&config.ClientConfig{}))
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "report.txt"), []byte("file content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tools := testTools(t, &config.APIConfig{BaseURL: srv.URL + "/v1", FileRoot: root})
+
+	tests := []struct {
+		tool     string
+		args     map[string]any
+		method   string
+		uri      string
+		jsonBody string
+	}{
+		{
+			tool:   "get_users_userId_prefs",
+			args:   map[string]any{"userId": "a/b?c"},
+			method: "GET",
+			uri:    "/v1/users/a%2Fb%3Fc/prefs",
+		},
+		{
+			tool:   "get_users",
+			args:   map[string]any{"search": "ann", "queries": []any{"limit(5)", "offset(5)"}, "limit": float64(5)},
+			method: "GET",
+			uri:    "/v1/users?limit=5&queries%5B%5D=limit%285%29&queries%5B%5D=offset%285%29&search=ann",
+		},
+		{
+			tool:     "post_users",
+			args:     map[string]any{"userId": "unique()", "labels": []any{"admin"}, "unknown": "dropped"},
+			method:   "POST",
+			uri:      "/v1/users",
+			jsonBody: `{"labels":["admin"],"userId":"unique()"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			*got = nil
+			if result := callTool(t, tools[tt.tool], tt.args); result.IsError {
+				t.Fatalf("error result: %s", resultText(result))
+			}
+			if len(*got) != 1 {
+				t.Fatalf("upstream saw %d requests", len(*got))
+			}
+			r := (*got)[0]
+			if r.method != tt.method || r.uri != tt.uri {
+				t.Errorf("request = %s %s, want %s %s", r.method, r.uri, tt.method, tt.uri)
+			}
+			if tt.jsonBody == "" {
+				if len(r.body) != 0 {
+					t.Errorf("unexpected body %q", r.body)
+				}
+				return
+			}
+			if r.contentType != "application/json" {
+				t.Errorf("content type = %q", r.contentType)
+			}
+			var body any
+			if err := json.Unmarshal(r.body, &body); err != nil {
+				t.Fatal(err)
+			}
+			if encoded, _ := json.Marshal(body); string(encoded) != tt.jsonBody {
+				t.Errorf("body = %s, want %s", encoded, tt.jsonBody)
+			}
+		})
+	}
+
+	t.Run("post_storage_buckets_bucketId_files", func(t *testing.T) {
+		*got = nil
+		result := callTool(t, tools["post_storage_buckets_bucketId_files"], map[string]any{
+			"bucketId":    "photos",
+			"fileId":      "unique()",
+			"file":        "report.txt",
+			"permissions": []any{`read("any")`},
+		})
+		if result.IsError {
+			t.Fatalf("error result: %s", resultText(result))
+		}
+		if len(*got) != 1 {
+			t.Fatalf("upstream saw %d requests", len(*got))
+		}
+		r := (*got)[0]
+		if r.method != "POST" || r.uri != "/v1/storage/buckets/photos/files" {
+			t.Errorf("request = %s %s", r.method, r.uri)
+		}
+		mediaType, params, err := mime.ParseMediaType(r.contentType)
+		if err != nil || mediaType != "multipart/form-data" {
+			t.Fatalf("content type = %q", r.contentType)
+		}
+		parts := map[string]string{}
+		reader := multipart.NewReader(strings.NewReader(string(r.body)), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := io.ReadAll(part)
+			key := part.FormName()
+			if part.FileName() != "" {
+				key += ":" + part.FileName()
+			}
+			parts[key] = string(data)
+		}
+		want := map[string]string{
+			"fileId":          "unique()",
+			"file:report.txt": "file content",
+			"permissions[]":   `read("any")`,
+		}
+		if len(parts) != len(want) {
+			t.Errorf("parts = %v, want %v", parts, want)
+		}
+		for k, v := range want {
+			if parts[k] != v {
+				t.Errorf("part %s = %q, want %q", k, parts[k], v)
+			}
+		}
+	})
+}
+
+func TestGenericHandlerErrors(t *testing.T) {
+	srv, got := upstream(t)
+	appwrite.SetDefault(appwrite.NewClient(&config.ClientConfig{}))
+	root := t.TempDir()
+	tools := testTools(t, &config.APIConfig{BaseURL: srv.URL + "/v1", FileRoot: root})
+
+	tests := []struct {
+		name string
+		tool string
+		args map[string]any
+		want string
+	}{
+		{"missing path parameter", "get_users_userId_prefs", map[string]any{}, "Missing required path parameter: userId"},
+		{"no arguments", "get_users_userId_prefs", nil, "Missing required path parameter: userId"},
+		{"invalid path parameter", "get_users_userId_prefs", map[string]any{"userId": 42.0}, "Invalid path parameter: userId"},
+		{"missing path parameter with body", "post_storage_buckets_bucketId_files", map[string]any{"fileId": "x", "file": "report.txt"}, "Missing required path parameter: bucketId"},
+		{"file outside FILE_ROOT", "post_storage_buckets_bucketId_files", map[string]any{"bucketId": "photos", "fileId": "x", "file": "../secret.txt"}, "Invalid file argument"},
+		{"file is not a path", "post_storage_buckets_bucketId_files", map[string]any{"bucketId": "photos", "fileId": "x", "file": 1.0}, "Invalid file argument: file must be a path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*got = nil
+			result := callTool(t, tools[tt.tool], tt.args)
+			if !result.IsError || !strings.HasPrefix(resultText(result), tt.want) {
+				t.Errorf("result = %q (error %v), want %q", resultText(result), result.IsError, tt.want)
+			}
+			if len(*got) != 0 {
+				t.Errorf("upstream saw %d requests", len(*got))
+			}
+		})
+	}
+}
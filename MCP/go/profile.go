@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// profileParam is the optional tool argument that selects a profile from
+// CONFIG_FILE.
+const profileParam = "profile"
+
+// withProfileParam adds the profile argument to def when cfg has profiles.
+func withProfileParam(def mcp.Tool, cfg *config.APIConfig) mcp.Tool {
+	if len(cfg.Profiles) == 0 {
+		return def
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	description := "Profile of the Appwrite project to use: " + strings.Join(sortedStrings(names), ", ")
+	if cfg.Profile != "" {
+		description += ". Defaults to " + cfg.Profile
+	}
+	props := make(map[string]any, len(def.InputSchema.Properties)+1)
+	for name, prop := range def.InputSchema.Properties {
+		props[name] = prop
+	}
+	props[profileParam] = map[string]any{
+		"type":        "string",
+		"description": description,
+		"enum":        sortedStrings(names),
+	}
+	def.InputSchema.Properties = props
+	return def
+}
+
+// withProfile returns ctx carrying the config of the profile a tool call
+// selects, and the call's arguments without the profile argument. Calls that
+// name no profile use the config of their HTTP request, or else the default
+// profile. A named profile always gets the server's FILE_ROOT. Clients bound
+// to a vault profile cannot switch profiles.
+func withProfile(ctx context.Context, request mcp.CallToolRequest, base *config.APIConfig) (context.Context, mcp.CallToolRequest, string, error) {
+	if len(base.Profiles) == 0 {
+		return ctx, request, "", nil
+	}
+	args := request.GetArguments()
+	name, _ := args[profileParam].(string)
+	if _, ok := args[profileParam]; ok {
+		rest := make(map[string]any, len(args))
+		for key, val := range args {
+			if key != profileParam {
+				rest[key] = val
+			}
+		}
+		request.Params.Arguments = rest
+	}
+
+	reqCfg := config.FromContext(ctx, nil)
+	if name == "" {
+		if reqCfg != nil || base.Profile == "" {
+			return ctx, request, "", nil
+		}
+		// The base config is the default profile with environment overrides
+		cfg := *base
+		cfg.Tools = append(append([]config.ToolFilter{}, base.Tools...), base.Profiles[base.Profile].Tools)
+		return config.WithContext(ctx, &cfg), request, base.Profile, nil
+	}
+	if id, ok := auth.FromContext(ctx); ok && id.Profile != "" {
+		return nil, request, "", fmt.Errorf("the %s argument is not available to clients bound to a vault profile", profileParam)
+	}
+	profile := base.Profiles[name]
+	if profile == nil {
+		return nil, request, "", fmt.Errorf("unknown profile %q", name)
+	}
+	// The profile's upstream is the server's own, so it gets FILE_ROOT even
+	// when the HTTP request sent its own base URL, whose config has none.
+	// Filters of the HTTP request still apply on top of the profile's own.
+	cfg := profile.APIConfig(base)
+	if reqCfg != nil {
+		cfg.Tools = append(append([]config.ToolFilter{}, reqCfg.Tools...), profile.Tools)
+	}
+	cfg.Profile = name
+	return config.WithContext(ctx, cfg), request, name, nil
+}
+
+func sortedStrings(list []string) []string {
+	sorted := append([]string{}, list...)
+	sort.Strings(sorted)
+	return sorted
+}
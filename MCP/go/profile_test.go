@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func profileBase(root string) *config.APIConfig {
+	return &config.APIConfig{
+		BaseURL:  "https://prod.example.com/v1",
+		Project:  "prod-project",
+		FileRoot: root,
+		Profile:  "prod",
+		Tools:    []config.ToolFilter{{Services: []string{"users", "teams"}}},
+		Profiles: map[string]*config.Profile{
+			"prod":    {BaseURL: "https://prod.example.com/v1", Project: "prod-project", Tools: config.ToolFilter{Deny: []string{"delete_*"}}},
+			"staging": {BaseURL: "https://staging.example.com/v1", Project: "staging-project", Tools: config.ToolFilter{ReadOnly: true}},
+		},
+	}
+}
+
+func callWith(args map[string]any) mcp.CallToolRequest {
+	var request mcp.CallToolRequest
+	request.Params.Arguments = args
+	return request
+}
+
+func TestWithProfile(t *testing.T) {
+	root := t.TempDir()
+	base := profileBase(root)
+
+	// A call that names no profile uses the default one
+	ctx, request, name, err := withProfile(context.Background(), callWith(map[string]any{"userId": "u1"}), base)
+	if err != nil || name != "prod" {
+		t.Fatalf("default: %q, %v", name, err)
+	}
+	cfg := config.FromContext(ctx, nil)
+	if cfg == nil || cfg.BaseURL != "https://prod.example.com/v1" || cfg.FileRoot != root || len(cfg.Tools) != 2 || cfg.Tools[1].Deny[0] != "delete_*" {
+		t.Errorf("default config = %+v", cfg)
+	}
+	if request.GetArguments()["userId"] != "u1" {
+		t.Errorf("arguments = %v", request.GetArguments())
+	}
+
+	// A named profile replaces the default and the argument is removed
+	ctx, request, name, err = withProfile(context.Background(), callWith(map[string]any{"userId": "u1", profileParam: "staging"}), base)
+	if err != nil || name != "staging" {
+		t.Fatalf("staging: %q, %v", name, err)
+	}
+	cfg = config.FromContext(ctx, nil)
+	if cfg.BaseURL != "https://staging.example.com/v1" || cfg.Project != "staging-project" || cfg.Profile != "staging" || cfg.FileRoot != root || !cfg.Tools[1].ReadOnly {
+		t.Errorf("staging config = %+v", cfg)
+	}
+	if _, ok := request.GetArguments()[profileParam]; ok || request.GetArguments()["userId"] != "u1" {
+		t.Errorf("arguments = %v", request.GetArguments())
+	}
+
+	if _, _, _, err := withProfile(context.Background(), callWith(map[string]any{profileParam: "dev"}), base); err == nil || err.Error() != `unknown profile "dev"` {
+		t.Errorf("unknown profile: %v", err)
+	}
+
+	// Without profiles calls are left alone
+	plain := &config.APIConfig{BaseURL: "https://cloud.appwrite.io/v1"}
+	ctx, _, name, err = withProfile(context.Background(), callWith(map[string]any{profileParam: "prod"}), plain)
+	if err != nil || name != "" || config.FromContext(ctx, nil) != nil {
+		t.Errorf("no profiles: %q, %v", name, err)
+	}
+}
+
+// A request that sent its own API_BASE_URL has a config without FILE_ROOT.
+// Its own calls keep it that way, but a profile it names is the server's
+// upstream, so it gets FILE_ROOT back while the request's filters still
+// apply.
+func TestWithProfileFromUntrustedRequest(t *testing.T) {
+	root := t.TempDir()
+	base := profileBase(root)
+	reqCfg := &config.APIConfig{
+		BaseURL:   "https://client.example.com/v1",
+		Untrusted: true,
+		Tools:     append(append([]config.ToolFilter{}, base.Tools...), config.ToolFilter{Allow: []string{"get_*"}}),
+	}
+	reqCtx := config.WithContext(context.Background(), reqCfg)
+
+	ctx, _, name, err := withProfile(reqCtx, callWith(map[string]any{}), base)
+	if err != nil || name != "" || config.FromContext(ctx, nil) != reqCfg {
+		t.Errorf("no profile named: the request config should apply (%q, %v)", name, err)
+	}
+
+	ctx, _, name, err = withProfile(reqCtx, callWith(map[string]any{profileParam: "staging"}), base)
+	if err != nil || name != "staging" {
+		t.Fatalf("staging: %q, %v", name, err)
+	}
+	cfg := config.FromContext(ctx, nil)
+	if cfg.BaseURL != "https://staging.example.com/v1" || cfg.Untrusted {
+		t.Errorf("config = %+v", cfg)
+	}
+	if cfg.FileRoot != root {
+		t.Errorf("FileRoot = %q, want the server's %q", cfg.FileRoot, root)
+	}
+	for _, tc := range []struct {
+		tool     string
+		service  string
+		readOnly bool
+		want     bool
+	}{
+		{"get_users", "users", true, true},
+		{"get_storage_buckets", "storage", true, false}, // environment filter
+		{"post_users", "users", false, false},           // request and profile filters
+		{"list_users", "users", true, false},            // request filter
+	} {
+		allowed := true
+		for _, f := range cfg.Tools {
+			allowed = allowed && f.Allows(tc.tool, tc.service, tc.readOnly)
+		}
+		if allowed != tc.want {
+			t.Errorf("%s allowed = %v, want %v", tc.tool, allowed, tc.want)
+		}
+	}
+}
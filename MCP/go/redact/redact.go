@@ -0,0 +1,235 @@
+// Package redact masks secrets in values that leave the process through
+// traces, logs or the audit log: tool arguments, URLs, headers and free
+// text that may contain a JWT.
+package redact
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces redacted values.
+const Mask = "[REDACTED]"
+
+// sensitiveParts are substrings of argument, header and query parameter
+// names whose values are secrets.
+var sensitiveParts = []string{
+	"password", "secret", "token", "jwt", "apikey", "api_key", "api-key",
+	"authorization", "cookie", "basic_auth", "bearer", "x-appwrite-key",
+	"credential", "private",
+}
+
+// Patterns of secrets wherever they appear in text: JSON Web Tokens,
+// credentials of an Authorization header and the user info of a URL.
+var (
+	jwtPattern        = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	credentialPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	userinfoPattern   = regexp.MustCompile(`(://)[^/?#@\s]+@`)
+)
+
+// minSecret is the shortest value Secret registers; masking shorter ones
+// would garble unrelated text.
+const minSecret = 6
+
+var secrets struct {
+	sync.RWMutex
+	values    []string        // Longest first, so a secret containing another is masked whole
+	permanent map[string]bool // Registered by Secret
+	refs      map[string]int  // Registrations by Register not released yet
+}
+
+// Sensitive reports whether values under the given name are secrets, e.g.
+// password, oldPassword, secret, API_KEY, BEARER_TOKEN or X-Appwrite-Key.
+func Sensitive(name string) bool {
+	lower := strings.ToLower(name)
+	for _, part := range sensitiveParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Secret registers configured secrets, such as API keys, JWTs, bearer
+// tokens and basic auth credentials, so String masks them verbatim.
+// Registered values are kept for the life of the process.
+func Secret(values ...string) {
+	secrets.Lock()
+	defer secrets.Unlock()
+	if secrets.permanent == nil {
+		secrets.permanent = map[string]bool{}
+	}
+	for _, v := range values {
+		if len(v) >= minSecret {
+			secrets.permanent[v] = true
+			add(v)
+		}
+	}
+}
+
+// Register registers secrets like Secret, but only until the returned
+// function is called: for credentials a client sent with a request, which
+// are masked for as long as the request is in progress. A value stays
+// masked while any registration of it is unreleased, or when Secret
+// registered it too.
+func Register(values ...string) (release func()) {
+	var registered []string
+	secrets.Lock()
+	if secrets.refs == nil {
+		secrets.refs = map[string]int{}
+	}
+	for _, v := range values {
+		if len(v) >= minSecret {
+			secrets.refs[v]++
+			add(v)
+			registered = append(registered, v)
+		}
+	}
+	secrets.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			secrets.Lock()
+			defer secrets.Unlock()
+			for _, v := range registered {
+				if secrets.refs[v]--; secrets.refs[v] > 0 {
+					continue
+				}
+				delete(secrets.refs, v)
+				if !secrets.permanent[v] {
+					remove(v)
+				}
+			}
+		})
+	}
+}
+
+// add inserts v into the masked values, keeping the longest first. The
+// caller holds the write lock.
+func add(v string) {
+	if contains(secrets.values, v) {
+		return
+	}
+	secrets.values = append(secrets.values, v)
+	sort.SliceStable(secrets.values, func(i, j int) bool {
+		return len(secrets.values[i]) > len(secrets.values[j])
+	})
+}
+
+// remove deletes v from the masked values. The caller holds the write lock.
+func remove(v string) {
+	for i, existing := range secrets.values {
+		if existing == v {
+			secrets.values = append(secrets.values[:i], secrets.values[i+1:]...)
+			return
+		}
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, existing := range values {
+		if existing == v {
+			return true
+		}
+	}
+	return false
+}
+
+// String masks registered secrets, JWTs, Bearer and Basic credentials and
+// the user info of URLs contained in s.
+func String(s string) string {
+	secrets.RLock()
+	for _, v := range secrets.values {
+		s = strings.ReplaceAll(s, v, Mask)
+	}
+	secrets.RUnlock()
+	s = jwtPattern.ReplaceAllString(s, Mask)
+	s = credentialPattern.ReplaceAllString(s, "$1 "+Mask)
+	return userinfoPattern.ReplaceAllString(s, "${1}"+Mask+"@")
+}
+
+// Args returns a copy of tool arguments with the values of sensitive names
+// masked and JWTs removed from strings, at any depth.
+func Args(args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+	return value("", args).(map[string]any)
+}
+
+// Echoes masks, in s, the values of sensitive tool arguments, such as a
+// password quoted back in an error message, then applies String.
+func Echoes(s string, args map[string]any) string {
+	var values []string
+	collect("", args, &values)
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Mask)
+	}
+	return String(s)
+}
+
+// collect appends the string values under sensitive names in v to values.
+func collect(name string, v any, values *[]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, item := range v {
+			collect(key, item, values)
+		}
+	case []any:
+		for _, item := range v {
+			collect(name, item, values)
+		}
+	case string:
+		if v != "" && name != "" && Sensitive(name) {
+			*values = append(*values, v)
+		}
+	}
+}
+
+func value(name string, v any) any {
+	if name != "" && Sensitive(name) {
+		return Mask
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = value(key, item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = value("", item)
+		}
+		return out
+	case string:
+		return String(v)
+	}
+	return v
+}
+
+// URL returns raw without user info, with sensitive query parameters masked
+// and JWTs removed.
+func URL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return String(raw)
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name := range query {
+			if Sensitive(name) {
+				query[name] = []string{Mask}
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return String(u.String())
+}
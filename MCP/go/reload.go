@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/appwrite/mcp-server/auth"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/appwrite/mcp-server/vault"
+)
+
+// state is what the server reads from the environment and the files it
+// names. A reload replaces it as a whole.
+type state struct {
+	cfg      *config.APIConfig
+	getTools func(*config.APIConfig) []models.Tool
+	tokens   *auth.Tokens               // Tokens accepted on the MCP endpoint, HTTP modes only
+	profiles map[string]*config.Profile // Vault profiles, HTTP modes only
+	secrets  []string                   // Credentials to register with redact once the state is accepted
+}
+
+// loadState reads the config, the tools and, in HTTP modes, the token file
+// and the vault. It has no side effects, so a bad reload leaves the running
+// state alone; the caller registers the secrets of a state it accepts.
+func loadState(remote bool) (*state, error) {
+	cfg, err := config.LoadAPIConfig()
+	if err != nil {
+		return nil, err
+	}
+	getTools, err := loadTools(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("tools: %v", err)
+	}
+	s := &state{cfg: cfg, getTools: getTools, tokens: &auth.Tokens{}, secrets: secrets(cfg, cfg.Profiles)}
+	if !remote {
+		return s, nil
+	}
+
+	if cfg.AuthTokensFile != "" {
+		if err := s.tokens.LoadFile(cfg.AuthTokensFile); err != nil {
+			return nil, fmt.Errorf("AUTH_TOKENS_FILE: %v", err)
+		}
+	}
+	if cfg.VaultFile != "" {
+		v, err := vault.Load(cfg.VaultFile, os.Getenv("VAULT_KEY"))
+		if err != nil {
+			return nil, fmt.Errorf("VAULT_FILE: %v", err)
+		}
+		for i, c := range v.Clients {
+			if err := s.tokens.Add(auth.Identity{Label: c.Label, Profile: c.Profile}, c.Token); err != nil {
+				return nil, fmt.Errorf("VAULT_FILE: clients[%d].token: %v", i, err)
+			}
+		}
+		s.secrets = append(s.secrets, secrets(nil, v.Profiles)...)
+		s.profiles = v.Profiles
+		slog.Info("Loaded vault", "clients", len(v.Clients), "profiles", len(v.Profiles))
+	}
+	// The profiles hold the operator's keys, so anonymous clients may not use them
+	if s.tokens.Len() == 0 && len(cfg.Profiles) > 0 {
+		slog.Warn("Profiles from CONFIG_FILE are disabled; set AUTH_TOKENS_FILE or VAULT_FILE to make them available")
+		cfg.Profile, cfg.Profiles = "", nil
+	}
+	return s, nil
+}
+
+// secrets lists the credentials in cfg and profiles. Registered with
+// redact, they are masked wherever they turn up in logs and traces.
+func secrets(cfg *config.APIConfig, profiles map[string]*config.Profile) []string {
+	var values []string
+	if cfg != nil {
+		values = append(values, cfg.Key, cfg.JWT, cfg.BearerToken, cfg.BasicAuth)
+		if _, password, ok := strings.Cut(cfg.BasicAuth, ":"); ok {
+			values = append(values, password)
+		}
+	}
+	for _, p := range profiles {
+		values = append(values, p.Key, p.JWT)
+	}
+	return values
+}
+
+// reloader applies a freshly loaded state to the running server.
+type reloader struct {
+	remote bool
+	live   *liveTools
+	up     *upstreams   // HTTP modes only
+	tokens *auth.Tokens // The set the auth middleware checks, HTTP modes only
+
+	mu sync.Mutex
+}
+
+// reload loads the state again and applies it, or returns why it was
+// rejected, in which case the previous state stays active.
+func (r *reloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, err := loadState(r.remote)
+	if err != nil {
+		return err
+	}
+	if r.remote && (s.tokens.Len() > 0) != (r.tokens.Len() > 0) {
+		return errors.New("enabling or disabling authentication requires a restart")
+	}
+	redact.Secret(s.secrets...)
+	if r.remote {
+		r.tokens.Replace(s.tokens)
+		r.up.set(s.cfg, s.profiles)
+	}
+	total, added, changed, removed := r.live.update(s.cfg, s.getTools)
+	slog.Info("Reloaded config", "tools", total, "added", added, "changed", changed, "removed", removed)
+	return nil
+}
+
+// watch reloads on SIGHUP and, if the config sets WatchInterval, whenever
+// one of the files it names changes. It never returns.
+func (r *reloader) watch() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	if interval := r.live.config().WatchInterval; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	seen := fileStamps(r.live.config())
+	for {
+		select {
+		case <-hup:
+			slog.Info("SIGHUP received, reloading config")
+		case <-tick:
+			stamps := fileStamps(r.live.config())
+			if stamps == seen {
+				continue
+			}
+			seen = stamps
+			slog.Info("Config files changed, reloading config")
+		}
+		if err := r.reload(); err != nil {
+			slog.Error("Config reload rejected, keeping the previous config", "error", err)
+		}
+		seen = fileStamps(r.live.config())
+	}
+}
+
+// fileStamps summarizes the size and modification time of the files cfg
+// reads, so changes can be detected by polling.
+func fileStamps(cfg *config.APIConfig) string {
+	var stamps string
+	for _, path := range []string{os.Getenv("CONFIG_FILE"), cfg.AuthTokensFile, cfg.VaultFile, cfg.SpecFile} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			stamps += fmt.Sprintf("%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		} else {
+			stamps += path + ":missing;"
+		}
+	}
+	return stamps
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/egress"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func toolNames(live *liveTools) []string {
+	var names []string
+	for name := range live.state.Load().tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	t.Setenv("CONFIG_FILE", path)
+	writeConfig(t, path, `
+profiles:
+  prod: {baseURL: "https://prod.example.com/v1", project: prod, key: first-key-0001}
+tools:
+  services: [teams]
+`)
+	st, err := loadState(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := &upstreams{egress: &egress.Policy{}}
+	up.set(st.cfg, st.profiles)
+	_, live := createMCPServer(st.cfg, "HTTP", st.getTools)
+	r := &reloader{remote: true, live: live, up: up, tokens: st.tokens}
+	cfg, tools := live.config(), toolNames(live)
+
+	for name, tc := range map[string]struct {
+		file, tokens string
+	}{
+		"unknown field": {file: `
+profiles:
+  prod: {baseURL: "https://prod.example.com/v1", project: prod, key: second-key-0002, region: eu}
+tools:
+  services: [users]
+`},
+		// Valid on its own, but refused when applied
+		"enables authentication": {file: `
+profiles:
+  prod: {baseURL: "https://prod.example.com/v1", project: prod, key: second-key-0002}
+tools:
+  services: [users]
+`, tokens: "ci:ci-token-0001\n"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			writeConfig(t, path, tc.file)
+			if tc.tokens != "" {
+				tokens := filepath.Join(dir, "tokens")
+				writeConfig(t, tokens, tc.tokens)
+				t.Setenv("AUTH_TOKENS_FILE", tokens)
+			}
+			if err := r.reload(); err == nil {
+				t.Fatal("reload accepted")
+			}
+			if live.config() != cfg {
+				t.Error("config replaced")
+			}
+			if got := toolNames(live); !slices.Equal(got, tools) {
+				t.Errorf("tools = %v, want %v", got, tools)
+			}
+			if base, _ := up.get(); base != cfg {
+				t.Error("upstream config replaced")
+			}
+			if got := redact.String("second-key-0002"); got != "second-key-0002" {
+				t.Errorf("rejected config registered its key as a secret: %q", got)
+			}
+		})
+	}
+}
+
+// notifiedSession is a client session that records the notifications it gets.
+type notifiedSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notifiedSession) SessionID() string { return "test" }
+func (s *notifiedSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *notifiedSession) Initialize()       {}
+func (s *notifiedSession) Initialized() bool { return true }
+
+func (s *notifiedSession) listChanged() int {
+	n := 0
+	for {
+		select {
+		case msg := <-s.notifications:
+			if msg.Method == mcp.MethodNotificationToolsListChanged {
+				n++
+			}
+		case <-time.After(20 * time.Millisecond):
+			return n
+		}
+	}
+}
+
+func TestUpdateNotifiesToolListChanged(t *testing.T) {
+	filtered := func(f config.ToolFilter) *config.APIConfig {
+		return &config.APIConfig{Tools: []config.ToolFilter{f}}
+	}
+	mcpSrv, live := createMCPServer(filtered(config.ToolFilter{Services: []string{"teams"}}), "HTTP", GetAll)
+	client := &notifiedSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := mcpSrv.RegisterSession(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name                    string
+		filter                  config.ToolFilter
+		added, removed, notices int
+	}{
+		{"unchanged", config.ToolFilter{Services: []string{"teams"}}, 0, 0, 0},
+		{"removed", config.ToolFilter{Services: []string{"teams"}, ReadOnly: true}, 0, -1, 1},
+		{"added", config.ToolFilter{Services: []string{"teams", "locale"}, ReadOnly: true}, -1, 0, 1},
+	} {
+		_, added, changed, removed := live.update(filtered(tc.filter), GetAll)
+		if changed != 0 || (tc.added == 0) != (added == 0) || (tc.removed == 0) != (removed == 0) {
+			t.Errorf("%s: added %d, changed %d, removed %d", tc.name, added, changed, removed)
+		}
+		if n := client.listChanged(); n != tc.notices {
+			t.Errorf("%s: %d list_changed notifications, want %d", tc.name, n, tc.notices)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/audit"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const replayUsage = `Usage: mcp-server replay [flags] <audit log>
+
+Runs the read-only tool calls recorded in an audit log again and prints their
+results. Calls that may modify data, and calls whose arguments were redacted,
+are skipped. The upstream comes from the environment and CONFIG_FILE, as for
+the server.
+
+Flags:
+`
+
+// replaySelection decides which audit log entries are replayed.
+type replaySelection struct {
+	tool, client string
+	since, until time.Time
+	lines        [][2]int // Inclusive ranges of line numbers, all lines when empty
+}
+
+func (s *replaySelection) matches(n int, e *audit.Entry) bool {
+	if s.tool != "" && e.Tool != s.tool || s.client != "" && e.Client != s.client {
+		return false
+	}
+	if !s.since.IsZero() && e.Time.Before(s.since) || !s.until.IsZero() && e.Time.After(s.until) {
+		return false
+	}
+	if len(s.lines) == 0 {
+		return true
+	}
+	for _, r := range s.lines {
+		if n >= r[0] && n <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// replay implements the replay command and returns the exit code.
+func replay(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, replayUsage)
+		flags.PrintDefaults()
+	}
+	profile := flags.String("profile", "", "profile from CONFIG_FILE to run the calls against (default: the default profile)")
+	sel := &replaySelection{}
+	flags.StringVar(&sel.tool, "tool", "", "only replay calls of this tool")
+	flags.StringVar(&sel.client, "client", "", "only replay calls by this client")
+	since := flags.String("since", "", "only replay calls made at or after this RFC 3339 time")
+	until := flags.String("until", "", "only replay calls made at or before this RFC 3339 time")
+	lines := flags.String("lines", "", "only replay these lines of the log, e.g. 3,7-9")
+	dryRun := flags.Bool("dry-run", false, "list the calls that would be replayed without running them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	var err error
+	if sel.since, err = parseTime(*since); err != nil {
+		fmt.Fprintf(stderr, "invalid -since: %v\n", err)
+		return 2
+	}
+	if sel.until, err = parseTime(*until); err != nil {
+		fmt.Fprintf(stderr, "invalid -until: %v\n", err)
+		return 2
+	}
+	if sel.lines, err = parseLines(*lines); err != nil {
+		fmt.Fprintf(stderr, "invalid -lines: %v\n", err)
+		return 2
+	}
+
+	cfg, err := config.LoadAPIConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	if *profile != "" && cfg.Profiles[*profile] == nil {
+		fmt.Fprintf(stderr, "unknown profile %q\n", *profile)
+		return 1
+	}
+	getTools, err := loadTools(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load tools: %v\n", err)
+		return 1
+	}
+	clientCfg, err := config.LoadClientConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load HTTP client config: %v\n", err)
+		return 1
+	}
+	appwrite.SetDefault(appwrite.NewClient(clientCfg))
+	tools := map[string]models.Tool{}
+	for _, tool := range getTools(cfg) {
+		tools[tool.Definition.Name] = tool
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for n := 1; scanner.Scan(); n++ {
+		var e audit.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			fmt.Fprintf(stderr, "line %d: %v\n", n, err)
+			continue
+		}
+		if !sel.matches(n, &e) {
+			continue
+		}
+		fmt.Fprintf(stdout, "== line %d: %s, recorded %s", n, e.Tool, e.Result)
+		if e.Status != 0 {
+			fmt.Fprintf(stdout, " (%d %s %s)", e.Status, e.Method, e.Path)
+		}
+		fmt.Fprintln(stdout)
+
+		tool, ok := tools[e.Tool]
+		switch {
+		case !ok:
+			fmt.Fprintln(stdout, "skipped: tool not available")
+		case tool.Definition.Annotations.ReadOnlyHint == nil || !*tool.Definition.Annotations.ReadOnlyHint:
+			fmt.Fprintln(stdout, "skipped: not read-only")
+		case hasRedacted(e.Arguments):
+			fmt.Fprintln(stdout, "skipped: arguments were redacted")
+		case *dryRun:
+			fmt.Fprintln(stdout, "would replay")
+		default:
+			result, err := replayCall(tool, cfg, *profile, e.Arguments)
+			if err != nil {
+				fmt.Fprintf(stdout, "replayed error: %v\n", err)
+				continue
+			}
+			status := "ok"
+			if result.IsError {
+				status = "error"
+			}
+			fmt.Fprintf(stdout, "replayed %s\n%s\n", status, resultContent(result))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// replayCall runs tool with args against profile, through the same profile
+// selection and tool filters as a call from a client.
+func replayCall(tool models.Tool, base *config.APIConfig, profile string, args map[string]any) (*mcp.CallToolResult, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	if profile != "" {
+		args[profileParam] = profile
+	}
+	var request mcp.CallToolRequest
+	request.Params.Name = tool.Definition.Name
+	request.Params.Arguments = args
+	if !allowed(tool, base.Tools) {
+		return nil, fmt.Errorf("tool %s is not enabled", tool.Definition.Name)
+	}
+	ctx, request := selectProfile(context.Background(), request, base)
+	return guard(tool)(ctx, request)
+}
+
+// hasRedacted reports whether any value in args was masked when logged.
+func hasRedacted(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, item := range v {
+			if hasRedacted(item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if hasRedacted(item) {
+				return true
+			}
+		}
+	case string:
+		return strings.Contains(v, redact.Mask)
+	}
+	return false
+}
+
+func resultContent(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		switch c := content.(type) {
+		case mcp.TextContent:
+			parts = append(parts, c.Text)
+		case mcp.ImageContent:
+			parts = append(parts, fmt.Sprintf("[image %s]", c.MIMEType))
+		default:
+			parts = append(parts, fmt.Sprintf("[%T]", content))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func parseTime(val string) (time.Time, error) {
+	if val == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, val)
+}
+
+// parseLines parses a list of line numbers and ranges such as "3,7-9".
+func parseLines(val string) ([][2]int, error) {
+	var ranges [][2]int
+	for _, part := range strings.Split(val, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(to); err != nil {
+				return nil, err
+			}
+		}
+		if first < 1 || last < first {
+			return nil, errors.New("bad range " + part)
+		}
+		ranges = append(ranges, [2]int{first, last})
+	}
+	return ranges, nil
+}
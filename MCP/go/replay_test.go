@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/audit"
+	"github.com/appwrite/mcp-server/redact"
+)
+
+// replayEnv points the environment replay loads its config from at the
+// upstream url, and returns the request URIs the upstream receives.
+func replayEnv(t *testing.T, url string) *[]string {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.RequestURI)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"total":0,"users":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	for _, name := range []string{"TRANSPORT", "transport", "CONFIG_FILE", "SPEC_FILE", "READ_ONLY", "TOOLS_ALLOW", "TOOLS_DENY", "TOOLS_SERVICES", "PROFILE"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("API_BASE_URL", srv.URL+url)
+	t.Setenv("APPWRITE_PROJECT", "test-project")
+	t.Setenv("APPWRITE_KEY", "test-key-0001")
+	return &got
+}
+
+// writeAudit records entries through the audit log, as the server does.
+func writeAudit(t *testing.T, entries ...audit.Entry) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := audit.Open(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range entries {
+		if err := l.Write(&entries[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+	return path
+}
+
+func runReplay(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := replay(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestReplay(t *testing.T) {
+	got := replayEnv(t, "/v1")
+	path := writeAudit(t,
+		audit.Entry{Tool: "get_users", ReadOnly: true, Arguments: map[string]any{"search": "ann"}, Method: "GET", Path: "/v1/users", Status: 200, Result: "ok"},
+		audit.Entry{Tool: "post_users", Arguments: map[string]any{"userId": "unique()"}, Result: "ok"},
+		audit.Entry{Tool: "save_avatars_qr", Arguments: map[string]any{"text": "hi", "savePath": "qr.png"}, Result: "ok"},
+		audit.Entry{Tool: "get_users", ReadOnly: true, Arguments: map[string]any{"search": redact.Mask}, Result: "ok"},
+		audit.Entry{Tool: "get_gone", ReadOnly: true, Result: "ok"},
+	)
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString("not json\n")
+	f.Close()
+
+	code, stdout, stderr := runReplay(t, path)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	for _, want := range []string{
+		"== line 1: get_users, recorded ok (200 GET /v1/users)\nreplayed ok\n",
+		"== line 2: post_users, recorded ok\nskipped: not read-only\n",
+		"== line 3: save_avatars_qr, recorded ok\nskipped: not read-only\n",
+		"== line 4: get_users, recorded ok\nskipped: arguments were redacted\n",
+		"== line 5: get_gone, recorded ok\nskipped: tool not available\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output lacks %q:\n%s", want, stdout)
+		}
+	}
+	if !strings.Contains(stderr, "line 6: ") {
+		t.Errorf("stderr = %q, want the invalid line reported", stderr)
+	}
+	if strings.Join(*got, "\n") != "GET /v1/users?search=ann" {
+		t.Errorf("upstream requests = %v, want only the read-only call", *got)
+	}
+}
+
+func TestReplaySelection(t *testing.T) {
+	got := replayEnv(t, "/v1")
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	path := writeAudit(t,
+		audit.Entry{Time: day, Tool: "get_users", Client: "ci", Arguments: map[string]any{"search": "a"}, Result: "ok"},
+		audit.Entry{Time: day.Add(time.Hour), Tool: "get_users", Client: "bot", Arguments: map[string]any{"search": "b"}, Result: "ok"},
+		audit.Entry{Time: day.Add(2 * time.Hour), Tool: "get_teams", Client: "ci", Result: "ok"},
+		audit.Entry{Time: day.Add(3 * time.Hour), Tool: "get_users", Client: "ci", Arguments: map[string]any{"search": "d"}, Result: "ok"},
+	)
+
+	tests := []struct {
+		name  string
+		args  []string
+		lines []string
+	}{
+		{"tool", []string{"-tool", "get_teams"}, []string{"3"}},
+		{"client", []string{"-client", "ci"}, []string{"1", "3", "4"}},
+		{"time", []string{"-since", "2026-01-02T01:00:00Z", "-until", "2026-01-02T02:00:00Z"}, []string{"2", "3"}},
+		{"lines", []string{"-lines", "1,3-4"}, []string{"1", "3", "4"}},
+		{"combined", []string{"-tool", "get_users", "-client", "ci", "-lines", "2-4"}, []string{"4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runReplay(t, append(append(tt.args, "-dry-run"), path)...)
+			if code != 0 {
+				t.Fatalf("exit code %d: %s", code, stderr)
+			}
+			var lines []string
+			for _, line := range strings.Split(stdout, "\n") {
+				if rest, ok := strings.CutPrefix(line, "== line "); ok {
+					lines = append(lines, rest[:strings.Index(rest, ":")])
+				}
+			}
+			if strings.Join(lines, ",") != strings.Join(tt.lines, ",") {
+				t.Errorf("replayed lines %v, want %v", lines, tt.lines)
+			}
+			if strings.Count(stdout, "would replay") != len(tt.lines) {
+				t.Errorf("output:\n%s", stdout)
+			}
+		})
+	}
+	if len(*got) != 0 {
+		t.Errorf("dry runs reached the upstream: %v", *got)
+	}
+
+	for _, args := range [][]string{
+		{"-since", "yesterday", path},
+		{"-lines", "4-2", path},
+		{},
+		{path, path},
+	} {
+		if code, _, _ := runReplay(t, args...); code != 2 {
+			t.Errorf("%v: exit code %d, want 2", args, code)
+		}
+	}
+}
+
+func TestReplayProfile(t *testing.T) {
+	prod := replayEnv(t, "/v1")
+	var staging []string
+	stagingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		staging = append(staging, r.Method+" "+r.RequestURI)
+		w.Write([]byte(`{"total":0,"users":[]}`))
+	}))
+	defer stagingSrv.Close()
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(configFile, []byte(`
+defaultProfile: prod
+profiles:
+  prod: {baseURL: "`+os.Getenv("API_BASE_URL")+`", project: prod}
+  staging:
+    baseURL: "`+stagingSrv.URL+`/v1"
+    project: staging
+    key: staging-key-0001
+    tools: {deny: [get_teams]}
+`), 0o600)
+	t.Setenv("API_BASE_URL", "")
+	t.Setenv("CONFIG_FILE", configFile)
+	path := writeAudit(t,
+		audit.Entry{Tool: "get_users", Arguments: map[string]any{"search": "ann"}, Result: "ok"},
+		audit.Entry{Tool: "get_teams", Result: "ok"},
+	)
+
+	code, stdout, stderr := runReplay(t, "-profile", "staging", path)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if strings.Join(staging, "\n") != "GET /v1/users?search=ann" || len(*prod) != 0 {
+		t.Errorf("staging got %v, prod got %v", staging, *prod)
+	}
+	if !strings.Contains(stdout, "replayed error\nTool get_teams is not enabled for profile staging") {
+		t.Errorf("filtered tool replayed:\n%s", stdout)
+	}
+
+	if code, _, stderr := runReplay(t, "-profile", "dev", path); code != 1 || !strings.Contains(stderr, `unknown profile "dev"`) {
+		t.Errorf("unknown profile: exit code %d, %s", code, stderr)
+	}
+}
+
+func TestReplayRespectsToolFilters(t *testing.T) {
+	got := replayEnv(t, "/v1")
+	t.Setenv("TOOLS_DENY", "get_users")
+	path := writeAudit(t, audit.Entry{Tool: "get_users", Arguments: map[string]any{"search": "ann"}, Result: "ok"})
+	code, stdout, stderr := runReplay(t, path)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "replayed error: tool get_users is not enabled") || len(*got) != 0 {
+		t.Errorf("denied tool replayed: %v\n%s", *got, stdout)
+	}
+}
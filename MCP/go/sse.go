@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sseConfigs keeps the upstream config each SSE connection was opened with,
+// so messages posted for the session may omit the configuration headers.
+type sseConfigs struct {
+	mu   sync.Mutex
+	cfgs map[string]*config.APIConfig
+}
+
+func newSSEConfigs() *sseConfigs {
+	return &sseConfigs{cfgs: map[string]*config.APIConfig{}}
+}
+
+// hooks records the config of a session when its SSE connection registers
+// and forgets it when the connection closes. Like in sessionStore, configs of
+// a CONFIG_FILE profile are resolved per message instead.
+func (s *sseConfigs) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		if cfg := config.FromContext(ctx, nil); cfg != nil && cfg.Profile == "" {
+			s.mu.Lock()
+			s.cfgs[session.SessionID()] = cfg
+			s.mu.Unlock()
+		}
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		s.mu.Lock()
+		delete(s.cfgs, session.SessionID())
+		s.mu.Unlock()
+	})
+	return hooks
+}
+
+func (s *sseConfigs) get(id string) *config.APIConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfgs[id]
+}
+
+// sseHandlers serves the /sse and /message endpoints of the HTTP+SSE
+// transport, attaching the upstream config to each request like mcpHandler.
+func sseHandlers(mcpSrv *server.MCPServer, up *upstreams, cfgs *sseConfigs) (sse, message http.Handler) {
+	sseSrv := server.NewSSEServer(mcpSrv,
+		server.WithSSEEndpoint("/sse"),
+		server.WithMessageEndpoint("/message"),
+	)
+	sse = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg, err := up.config(r)
+		if err != nil {
+			configError(w, r, err)
+			return
+		}
+		if cfg == nil {
+			cfg = up.fallback(r)
+		}
+		if cfg == nil {
+			http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
+			return
+		}
+		logUpstream(r, cfg, "Incoming SSE connection")
+		// Messages without configuration headers use this config for as
+		// long as the connection is open
+		defer registerSecrets(cfg)()
+		sseSrv.SSEHandler().ServeHTTP(w, r.WithContext(config.WithContext(r.Context(), cfg)))
+	})
+	message = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg, err := up.config(r)
+		if err != nil {
+			configError(w, r, err)
+			return
+		}
+		if cfg == nil {
+			cfg = cfgs.get(r.URL.Query().Get("sessionId"))
+		}
+		if cfg == nil {
+			cfg = up.fallback(r)
+		}
+		if cfg != nil {
+			r = r.WithContext(config.WithContext(r.Context(), cfg))
+			defer registerSecrets(cfg)()
+		}
+		// Messages for unknown sessions are rejected by the SSE server
+		sseSrv.MessageHandler().ServeHTTP(w, r)
+	})
+	return sse, message
+}
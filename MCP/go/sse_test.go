@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/egress"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sseServer serves /sse and /message with a probe tool that returns the base
+// URL of the config its call runs with.
+func sseServer(t *testing.T) (*httptest.Server, *sseConfigs) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+	up := &upstreams{egress: &egress.Policy{AllowNetwork: []*net.IPNet{loopback}}}
+	up.set(&config.APIConfig{}, nil)
+	cfgs := newSSEConfigs()
+	mcpSrv := server.NewMCPServer("test", "0", server.WithHooks(cfgs.hooks()))
+	mcpSrv.AddTool(mcp.NewTool("probe"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("base=" + config.FromContext(ctx, &config.APIConfig{}).BaseURL), nil
+	})
+	sse, message := sseHandlers(mcpSrv, up, cfgs)
+	mux := http.NewServeMux()
+	mux.Handle("/sse", sse)
+	mux.Handle("/message", message)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, cfgs
+}
+
+// sseStream is an open SSE connection.
+type sseStream struct {
+	events   chan string
+	endpoint string
+	cancel   context.CancelFunc
+}
+
+func connectSSE(t *testing.T, srv *httptest.Server, baseURL string) *sseStream {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/sse", nil)
+	if baseURL != "" {
+		req.Header.Set("API_BASE_URL", baseURL)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		t.Fatalf("connect: status = %d", resp.StatusCode)
+	}
+	s := &sseStream{events: make(chan string, 16), cancel: cancel}
+	go func() {
+		defer resp.Body.Close()
+		defer close(s.events)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				s.events <- data
+			}
+		}
+	}()
+	s.endpoint = srv.URL + s.next(t)
+	return s
+}
+
+func (s *sseStream) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case data, ok := <-s.events:
+		if !ok {
+			t.Fatal("stream closed")
+		}
+		return data
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+	return ""
+}
+
+func (s *sseStream) post(t *testing.T, body string, headers map[string]string) int {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, s.endpoint, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+const probeCall = `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"probe","arguments":{}}}`
+
+func TestSSEMessagesUseConnectionConfig(t *testing.T) {
+	srv, cfgs := sseServer(t)
+	s := connectSSE(t, srv, "http://127.0.0.1:8080/v1")
+	id := s.endpoint[strings.Index(s.endpoint, "sessionId=")+len("sessionId="):]
+	if cfg := cfgs.get(id); cfg == nil || cfg.BaseURL != "http://127.0.0.1:8080/v1" {
+		t.Fatalf("config of session %s = %+v", id, cfg)
+	}
+
+	if code := s.post(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`, nil); code != http.StatusAccepted {
+		t.Fatalf("initialize: status = %d", code)
+	}
+	s.next(t)
+
+	// Messages without configuration headers use the config of the connection
+	if code := s.post(t, probeCall, nil); code != http.StatusAccepted {
+		t.Fatalf("call: status = %d", code)
+	}
+	if event := s.next(t); !strings.Contains(event, "base=http://127.0.0.1:8080/v1") {
+		t.Errorf("call without headers: %s", event)
+	}
+
+	// Headers of a message take precedence
+	if code := s.post(t, probeCall, map[string]string{"API_BASE_URL": "http://127.0.0.1:9090/v1"}); code != http.StatusAccepted {
+		t.Fatalf("call: status = %d", code)
+	}
+	if event := s.next(t); !strings.Contains(event, "base=http://127.0.0.1:9090/v1") {
+		t.Errorf("call with headers: %s", event)
+	}
+
+	// Closing the connection forgets its config
+	s.cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for cfgs.get(id) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("config kept after the connection closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSSERejectsUnknownSession(t *testing.T) {
+	srv, _ := sseServer(t)
+	s := &sseStream{endpoint: srv.URL + "/message?sessionId=unknown"}
+	if code := s.post(t, ping, nil); code != http.StatusBadRequest {
+		t.Errorf("unknown session: status = %d, want 400", code)
+	}
+	// A configuration header does not make up for the session
+	if code := s.post(t, ping, map[string]string{"API_BASE_URL": "http://127.0.0.1:8080/v1"}); code != http.StatusBadRequest {
+		t.Errorf("unknown session with config: status = %d, want 400", code)
+	}
+}
+
+func TestSSERequiresConfig(t *testing.T) {
+	srv, _ := sseServer(t)
+	resp, err := http.Get(srv.URL + "/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/sse", nil)
+	req.Header.Set("API_BASE_URL", "http://169.254.169.254/latest")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("blocked base URL: status = %d, want 403", resp.StatusCode)
+	}
+}
@@ -1,12 +1,11 @@
+// Code generated by mcpgen from openapi.yaml. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
+	"github.com/appwrite/mcp-server/appwrite"
 	"github.com/appwrite/mcp-server/config"
 	"github.com/appwrite/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -14,55 +13,29 @@ import (
 
 func AccountdeleteHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/account", cfg.BaseURL)
-		req, err := http.NewRequest("DELETE", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			req.Header.Set("X-Appwrite-JWT", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
 		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return appwrite.Call(ctx, cfg, &appwrite.Request{
+			Method:   "DELETE",
+			Path:     "/account",
+			Security: []string{"JWT", "Project"},
+			Scope:    "account",
+		}, &result)
 	}
 }
 
 func CreateAccountdeleteTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("delete_account",
 		mcp.WithDescription("Delete Account"),
+		mcp.WithTitleAnnotation("Delete Account"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
 	)
 
 	return models.Tool{
 		Definition: tool,
+		Service:    "account",
 		Handler:    AccountdeleteHandler(cfg),
 	}
 }
@@ -1,12 +1,13 @@
+// Code generated by mcpgen from openapi.yaml. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"net/url"
 
+	"github.com/appwrite/mcp-server/appwrite"
 	"github.com/appwrite/mcp-server/config"
 	"github.com/appwrite/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -26,56 +27,30 @@ func AccountdeletesessionHandler(cfg *config.APIConfig) func(ctx context.Context
 		if !ok {
 			return mcp.NewToolResultError("Invalid path parameter: sessionId"), nil
 		}
-		url := fmt.Sprintf("%s/account/sessions/%s", cfg.BaseURL, sessionId)
-		req, err := http.NewRequest("DELETE", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			req.Header.Set("X-Appwrite-JWT", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
 		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return appwrite.Call(ctx, cfg, &appwrite.Request{
+			Method:   "DELETE",
+			Path:     fmt.Sprintf("/account/sessions/%s", url.PathEscape(sessionId)),
+			Security: []string{"JWT", "Project"},
+			Scope:    "account",
+		}, &result)
 	}
 }
 
 func CreateAccountdeletesessionTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("delete_account_sessions_sessionId",
 		mcp.WithDescription("Delete Account Session"),
+		mcp.WithTitleAnnotation("Delete Account Session"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
 		mcp.WithString("sessionId", mcp.Required(), mcp.Description("Session unique ID. Use the string 'current' to delete the current device session.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
+		Service:    "account",
 		Handler:    AccountdeletesessionHandler(cfg),
 	}
 }
@@ -1,13 +1,13 @@
+// Code generated by mcpgen from openapi.yaml. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
+	"net/url"
 
+	"github.com/appwrite/mcp-server/appwrite"
 	"github.com/appwrite/mcp-server/config"
 	"github.com/appwrite/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -27,65 +27,25 @@ func AvatarsgetcreditcardHandler(cfg *config.APIConfig) func(ctx context.Context
 		if !ok {
 			return mcp.NewToolResultError("Invalid path parameter: code"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["width"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("width=%v", val))
-		}
-		if val, ok := args["height"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("height=%v", val))
-		}
-		if val, ok := args["quality"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("quality=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/avatars/credit-cards/%s%s", cfg.BaseURL, code, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			req.Header.Set("X-Appwrite-Key", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		query := appwrite.QueryFromArgs(args, "width", "height", "quality")
+		return appwrite.CallBinary(ctx, cfg, &appwrite.Request{
+			Method:   "GET",
+			Path:     fmt.Sprintf("/avatars/credit-cards/%s", url.PathEscape(code)),
+			Query:    query,
+			Security: []string{"JWT", "Key", "Project"},
+			Scope:    "avatars.read",
+		})
 	}
 }
 
 func CreateAvatarsgetcreditcardTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_avatars_credit-cards_code",
 		mcp.WithDescription("Get Credit Card Icon"),
+		mcp.WithTitleAnnotation("Get Credit Card Icon"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
 		mcp.WithString("code", mcp.Required(), mcp.Description("Credit Card Code. Possible values: amex, argencard, cabal, censosud, diners, discover, elo, hipercard, jcb, mastercard, naranja, targeta-shopping, union-china-pay, visa, mir, maestro.")),
 		mcp.WithNumber("width", mcp.Description("Image width. Pass an integer between 0 to 2000. Defaults to 100.")),
 		mcp.WithNumber("height", mcp.Description("Image height. Pass an integer between 0 to 2000. Defaults to 100.")),
@@ -94,6 +54,7 @@ func CreateAvatarsgetcreditcardTool(cfg *config.APIConfig) models.Tool {
 
 	return models.Tool{
 		Definition: tool,
+		Service:    "avatars",
 		Handler:    AvatarsgetcreditcardHandler(cfg),
 	}
 }
@@ -1,13 +1,11 @@
+// Code generated by mcpgen from openapi.yaml. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
+	"github.com/appwrite/mcp-server/appwrite"
 	"github.com/appwrite/mcp-server/config"
 	"github.com/appwrite/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,65 +17,25 @@ func AvatarsgetimageHandler(cfg *config.APIConfig) func(ctx context.Context, req
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["url"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("url=%v", val))
-		}
-		if val, ok := args["width"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("width=%v", val))
-		}
-		if val, ok := args["height"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("height=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/avatars/image%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			req.Header.Set("X-Appwrite-JWT", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		query := appwrite.QueryFromArgs(args, "url", "width", "height")
+		return appwrite.CallBinary(ctx, cfg, &appwrite.Request{
+			Method:   "GET",
+			Path:     "/avatars/image",
+			Query:    query,
+			Security: []string{"JWT", "Key", "Project"},
+			Scope:    "avatars.read",
+		})
 	}
 }
 
 func CreateAvatarsgetimageTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_avatars_image",
 		mcp.WithDescription("Get Image from URL"),
+		mcp.WithTitleAnnotation("Get Image from URL"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
 		mcp.WithString("url", mcp.Required(), mcp.Description("Image URL which you want to crop.")),
 		mcp.WithNumber("width", mcp.Description("Resize preview image width, Pass an integer between 0 to 2000.")),
 		mcp.WithNumber("height", mcp.Description("Resize preview image height, Pass an integer between 0 to 2000.")),
@@ -85,6 +43,7 @@ func CreateAvatarsgetimageTool(cfg *config.APIConfig) models.Tool {
 
 	return models.Tool{
 		Definition: tool,
+		Service:    "avatars",
 		Handler:    AvatarsgetimageHandler(cfg),
 	}
 }
@@ -1,13 +1,11 @@
+// Code generated by mcpgen from openapi.yaml. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
+	"github.com/appwrite/mcp-server/appwrite"
 	"github.com/appwrite/mcp-server/config"
 	"github.com/appwrite/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,68 +17,26 @@ func DatabaselistcollectionsHandler(cfg *config.APIConfig) func(ctx context.Cont
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["search"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("search=%v", val))
-		}
-		if val, ok := args["limit"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("limit=%v", val))
-		}
-		if val, ok := args["offset"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("offset=%v", val))
-		}
-		if val, ok := args["orderType"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("orderType=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/database/collections%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			req.Header.Set("X-Appwrite-Key", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
+		query := appwrite.QueryFromArgs(args, "search", "limit", "offset", "orderType")
 		var result models.CollectionList
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return appwrite.Call(ctx, cfg, &appwrite.Request{
+			Method:   "GET",
+			Path:     "/database/collections",
+			Query:    query,
+			Security: []string{"Key", "Project"},
+			Scope:    "collections.read",
+		}, &result)
 	}
 }
 
 func CreateDatabaselistcollectionsTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("get_database_collections",
 		mcp.WithDescription("List Collections"),
+		mcp.WithTitleAnnotation("List Collections"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
 		mcp.WithString("search", mcp.Description("Search term to filter your list results. Max length: 256 chars.")),
 		mcp.WithNumber("limit", mcp.Description("Results limit value. By default will return maximum 25 results. Maximum of 100 results allowed per request.")),
 		mcp.WithNumber("offset", mcp.Description("Results offset. The default value is 0. Use this param to manage pagination.")),
@@ -89,6 +45,7 @@ func CreateDatabaselistcollectionsTool(cfg *config.APIConfig) models.Tool {
 
 	return models.Tool{
 		Definition: tool,
+		Service:    "database",
 		Handler:    DatabaselistcollectionsHandler(cfg),
 	}
 }
@@ -1,13 +1,11 @@
+// Code generated by mcpgen from openapi.yaml. DO NOT EDIT.
+
 package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"bytes"
 
+	"github.com/appwrite/mcp-server/appwrite"
 	"github.com/appwrite/mcp-server/config"
 	"github.com/appwrite/mcp-server/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,79 +17,37 @@ func FunctionscreateHandler(cfg *config.APIConfig) func(ctx context.Context, req
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		// Create properly typed request body using the generated schema
-		var requestBody map[string]interface{}
-		
-		// Optimized: Single marshal/unmarshal with JSON tags handling field mapping
-		if argsJSON, err := json.Marshal(args); err == nil {
-			if err := json.Unmarshal(argsJSON, &requestBody); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
-			}
-		} else {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal arguments: %v", err)), nil
-		}
-		
-		bodyBytes, err := json.Marshal(requestBody)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to encode request body", err), nil
-		}
-		url := fmt.Sprintf("%s/functions", cfg.BaseURL)
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyBytes))
-		req.Header.Set("Content-Type", "application/json")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		// Fallback to single auth parameter
-		if cfg.APIKey != "" {
-			req.Header.Set("X-Appwrite-Key", cfg.APIKey)
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
 		var result models.Function
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return appwrite.Call(ctx, cfg, &appwrite.Request{
+			Method:   "POST",
+			Path:     "/functions",
+			JSON:     args,
+			Security: []string{"Key", "Project"},
+			Scope:    "functions.write",
+		}, &result)
 	}
 }
 
 func CreateFunctionscreateTool(cfg *config.APIConfig) models.Tool {
 	tool := mcp.NewTool("post_functions",
 		mcp.WithDescription("Create Function"),
+		mcp.WithTitleAnnotation("Create Function"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithArray("events", mcp.Description("Input parameter: Events list."), mcp.WithStringItems()),
+		mcp.WithArray("execute", mcp.Required(), mcp.Description("Input parameter: An array of strings with execution permissions. By default no user is granted with any execute permissions. [learn more about permissions](/docs/permissions) and get a full list of available permissions."), mcp.WithStringItems()),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Input parameter: Function name. Max length: 128 chars.")),
+		mcp.WithString("runtime", mcp.Required(), mcp.Description("Input parameter: Execution runtime.")),
 		mcp.WithString("schedule", mcp.Description("Input parameter: Schedule CRON syntax.")),
 		mcp.WithNumber("timeout", mcp.Description("Input parameter: Function maximum execution time in seconds.")),
 		mcp.WithObject("vars", mcp.Description("Input parameter: Key-value JSON object.")),
-		mcp.WithArray("events", mcp.Description("Input parameter: Events list.")),
-		mcp.WithArray("execute", mcp.Required(), mcp.Description("Input parameter: An array of strings with execution permissions. By default no user is granted with any execute permissions. [learn more about permissions](/docs/permissions) and get a full list of available permissions.")),
-		mcp.WithString("name", mcp.Required(), mcp.Description("Input parameter: Function name. Max length: 128 chars.")),
-		mcp.WithString("runtime", mcp.Required(), mcp.Description("Input parameter: Execution runtime.")),
 	)
 
 	return models.Tool{
 		Definition: tool,
+		Service:    "functions",
 		Handler:    FunctionscreateHandler(cfg),
 	}
 }
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/files"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func FunctionscreatetagHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		functionIdVal, ok := args["functionId"]
+		if !ok {
+			return mcp.NewToolResultError("Missing required path parameter: functionId"), nil
+		}
+		functionId, ok := functionIdVal.(string)
+		if !ok {
+			return mcp.NewToolResultError("Invalid path parameter: functionId"), nil
+		}
+		command, err := request.RequireString("command")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := request.RequireString("path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		code, filename, err := files.Package(config.FromContext(ctx, cfg).FileRoot, path)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to package code", err), nil
+		}
+		defer code.Close()
+
+		body, contentType := files.Multipart([]files.Part{
+			{Name: "command", Value: command},
+			{Name: "code", Filename: filename, Reader: code},
+		})
+		defer body.Close()
+
+		resp, err := appwrite.Default().Do(ctx, cfg, &appwrite.Request{
+			Method:      "POST",
+			Path:        fmt.Sprintf("/functions/%s/tags", url.PathEscape(functionId)),
+			Body:        body,
+			ContentType: contentType,
+			Security:    []string{"Key", "Project"},
+			Scope:       "functions.write",
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+		}
+		var result models.Tag
+		if resp.StatusCode >= 400 || !request.GetBool("activate", false) {
+			return appwrite.Result(resp, &result)
+		}
+		if err := json.Unmarshal(resp.Body, &result); err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to decode created tag", err), nil
+		}
+
+		// Activation goes through the regular patch_functions_functionId_tag handler
+		activate := mcp.CallToolRequest{}
+		activate.Params.Name = "patch_functions_functionId_tag"
+		activate.Params.Arguments = map[string]any{"functionId": functionId, "tag": result.Id}
+		activated, err := FunctionsupdatetagHandler(cfg)(ctx, activate)
+		if err != nil {
+			return nil, err
+		}
+		if activated.IsError {
+			return mcp.NewToolResultError(fmt.Sprintf("Tag %s was created but could not be activated: %s", result.Id, models.ResultText(activated))), nil
+		}
+
+		var function models.Function
+		if err := json.Unmarshal([]byte(models.ResultText(activated)), &function); err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to decode activated function", err), nil
+		}
+		prettyJSON, err := json.MarshalIndent(map[string]any{"tag": result, "function": function}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+		}
+		return mcp.NewToolResultText(string(prettyJSON)), nil
+	}
+}
+
+func CreateFunctionscreatetagTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("post_functions_functionId_tags",
+		mcp.WithDescription("Create Tag"),
+		mcp.WithTitleAnnotation("Create Tag"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString("functionId", mcp.Required(), mcp.Description("Function unique ID.")),
+		mcp.WithString("command", mcp.Required(), mcp.Description("Code execution command.")),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Code directory or .tar.gz package, relative to FILE_ROOT. Directories are packaged as a .tar.gz automatically.")),
+		mcp.WithBoolean("activate", mcp.Description("Make the new tag the function's active tag once uploaded. Defaults to false.")),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Service:    "functions",
+		Handler:    FunctionscreatetagHandler(cfg),
+	}
+}
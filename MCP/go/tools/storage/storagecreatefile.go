@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/appwrite/mcp-server/appwrite"
+	"github.com/appwrite/mcp-server/config"
+	"github.com/appwrite/mcp-server/files"
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func StoragecreatefileHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		source := files.Source{
+			Content:  request.GetString("content", ""),
+			Path:     request.GetString("path", ""),
+			Filename: request.GetString("filename", ""),
+		}
+		content, filename, err := source.Open(config.FromContext(ctx, cfg).FileRoot)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("Invalid file argument", err), nil
+		}
+		defer content.Close()
+
+		parts := []files.Part{{Name: "file", Filename: filename, Reader: content}}
+		for _, name := range []string{"read", "write"} {
+			val, ok := args[name]
+			if !ok {
+				continue
+			}
+			values, err := files.Strings(val)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter: %s: %v", name, err)), nil
+			}
+			for _, v := range values {
+				parts = append(parts, files.Part{Name: name + "[]", Value: v})
+			}
+		}
+		body, contentType := files.Multipart(parts)
+		defer body.Close()
+
+		var result models.File
+		return appwrite.Call(ctx, cfg, &appwrite.Request{
+			Method:      "POST",
+			Path:        "/storage/files",
+			Body:        body,
+			ContentType: contentType,
+			Security:    []string{"JWT", "Key", "Project"},
+			Scope:       "files.write",
+		}, &result)
+	}
+}
+
+func CreateStoragecreatefileTool(cfg *config.APIConfig) models.Tool {
+	tool := mcp.NewTool("post_storage_files",
+		mcp.WithDescription("Create File"),
+		mcp.WithTitleAnnotation("Create File"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString("content", mcp.Description("Base64 encoded file content. Pass either content or path.")),
+		mcp.WithString("path", mcp.Description("Path of a local file to upload, relative to FILE_ROOT. Pass either content or path.")),
+		mcp.WithString("filename", mcp.Description("File name to store. Defaults to the base name of path, or \"file\" for inline content.")),
+		mcp.WithArray("read", mcp.Description("An array of strings with read permissions. By default only the current user is granted with read permissions. [learn more about permissions](/docs/permissions) and get a full list of available permissions."), mcp.WithStringItems()),
+		mcp.WithArray("write", mcp.Description("An array of strings with write permissions. By default only the current user is granted with write permissions. [learn more about permissions](/docs/permissions) and get a full list of available permissions."), mcp.WithStringItems()),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Service:    "storage",
+		Handler:    StoragecreatefileHandler(cfg),
+	}
+}
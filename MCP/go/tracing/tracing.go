@@ -0,0 +1,195 @@
+// Package tracing records OpenTelemetry spans for tool calls and the Appwrite
+// requests they make, and propagates the trace context to Appwrite.
+//
+// Exporters are chosen with OTEL_TRACES_EXPORTER, a comma-separated list of:
+//
+//	none     no tracing (the default)
+//	otlp     OTLP over HTTP, configured with the standard OTEL_EXPORTER_OTLP_* variables
+//	console  JSON to stdout, or to stderr in STDIO mode where stdout carries MCP
+//	file     JSON lines appended to OTEL_TRACES_FILE
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/appwrite/mcp-server/models"
+	"github.com/appwrite/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "appwrite-mcp-server"
+	tracerName  = "github.com/appwrite/mcp-server"
+
+	// maxAttribute caps the length of argument and error attributes
+	maxAttribute = 4096
+)
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Setup installs the tracer provider for the exporters in
+// OTEL_TRACES_EXPORTER and the W3C trace context propagator. stdio routes
+// the console exporter to stderr. The returned function flushes and stops
+// the exporters.
+func Setup(ctx context.Context, stdio bool) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var closers []io.Closer
+	var opts []sdktrace.TracerProviderOption
+	for _, name := range strings.Split(os.Getenv("OTEL_TRACES_EXPORTER"), ",") {
+		var exporter sdktrace.SpanExporter
+		switch name = strings.TrimSpace(name); name {
+		case "", "none":
+			continue
+		case "otlp":
+			exporter, err = otlptracehttp.New(ctx)
+		case "console":
+			var out io.Writer = os.Stdout
+			if stdio {
+				out = os.Stderr
+			}
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(out))
+		case "file":
+			path := os.Getenv("OTEL_TRACES_FILE")
+			if path == "" {
+				return nil, errors.New("OTEL_TRACES_EXPORTER=file requires OTEL_TRACES_FILE")
+			}
+			var f *os.File
+			f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+			if err == nil {
+				closers = append(closers, f)
+				exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+			}
+		default:
+			return nil, fmt.Errorf("invalid OTEL_TRACES_EXPORTER %q: use none, otlp, console or file", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("create %s trace exporter: %v", name, err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	if len(opts) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence
+	if env, err := resource.New(ctx, resource.WithFromEnv()); err == nil {
+		res, _ = resource.Merge(res, env)
+	}
+	provider := sdktrace.NewTracerProvider(append(opts, sdktrace.WithResource(res))...)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		for _, c := range closers {
+			c.Close()
+		}
+		return err
+	}, nil
+}
+
+// Instrument wraps the handler of tool in a span carrying the tool name,
+// the redacted arguments and the outcome. Upstream requests made by the
+// handler become child spans.
+func Instrument(tool models.Tool) models.Tool {
+	name := tool.Definition.Name
+	next := tool.Handler
+	tool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := tracer().Start(ctx, "tools/call "+name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("mcp.method.name", "tools/call"),
+				attribute.String("mcp.tool.name", name),
+				attribute.String("mcp.tool.arguments", arguments(request)),
+			),
+		)
+		defer span.End()
+
+		result, err := next(ctx, request)
+		switch {
+		case err != nil:
+			fail(span, redact.Echoes(err.Error(), request.GetArguments()))
+			span.SetAttributes(attribute.String("mcp.tool.status", "error"))
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, truncate(redact.Echoes(models.ResultText(result), request.GetArguments())))
+			span.SetAttributes(attribute.String("mcp.tool.status", "error"))
+		default:
+			span.SetAttributes(attribute.String("mcp.tool.status", "ok"))
+		}
+		return result, err
+	}
+	return tool
+}
+
+// Upstream starts a client span for an Appwrite request and writes the
+// trace context into its headers. The returned function ends the span with
+// the response, or the error if there was none.
+func Upstream(req *http.Request) func(resp *http.Response, err error) {
+	ctx, span := tracer().Start(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(redact.URL(req.URL.String())),
+			semconv.ServerAddress(req.URL.Hostname()),
+		),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return func(resp *http.Response, err error) {
+		defer span.End()
+		if err != nil {
+			fail(span, redact.String(err.Error()))
+			return
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+}
+
+// fail marks span as failed with an already redacted error message. The
+// original error is not recorded, as its text may hold secrets.
+func fail(span trace.Span, msg string) {
+	msg = truncate(msg)
+	span.RecordError(errors.New(msg))
+	span.SetStatus(codes.Error, msg)
+}
+
+// arguments encodes the redacted arguments of a call for a span attribute.
+func arguments(request mcp.CallToolRequest) string {
+	encoded, err := json.Marshal(redact.Args(request.GetArguments()))
+	if err != nil {
+		return ""
+	}
+	return truncate(string(encoded))
+}
+
+func truncate(s string) string {
+	if len(s) <= maxAttribute {
+		return s
+	}
+	return s[:maxAttribute] + "..."
+}
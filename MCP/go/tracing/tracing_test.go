@@ -0,0 +1,169 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/appwrite/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a tracer provider that keeps finished spans in memory.
+func record(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return exporter
+}
+
+func attr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// fetchTool requests path from srv through Upstream and fails when the
+// status is not 200.
+func fetchTool(srv *httptest.Server, path string) models.Tool {
+	return models.Tool{
+		Definition: mcp.NewTool("get_thing"),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+			end := Upstream(req)
+			resp, err := http.DefaultClient.Do(req)
+			end(resp, err)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return mcp.NewToolResultError("Appwrite returned " + resp.Status), nil
+			}
+			return mcp.NewToolResultText("{}"), nil
+		},
+	}
+}
+
+func call(t *testing.T, tool models.Tool, args map[string]any) {
+	t.Helper()
+	var request mcp.CallToolRequest
+	request.Params.Name = tool.Definition.Name
+	request.Params.Arguments = args
+	Instrument(tool).Handler(context.Background(), request)
+}
+
+func TestInstrument(t *testing.T) {
+	exporter := record(t)
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	call(t, fetchTool(srv, "/thing?apiKey=s3cr3t-value"), map[string]any{"thingId": "t1", "password": "hunter22"})
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	client, server := spans[0], spans[1]
+
+	if server.Name != "tools/call get_thing" || server.SpanKind != trace.SpanKindServer {
+		t.Errorf("tool span = %s (%v)", server.Name, server.SpanKind)
+	}
+	if got := attr(server, "mcp.tool.name").AsString(); got != "get_thing" {
+		t.Errorf("mcp.tool.name = %q", got)
+	}
+	if got := attr(server, "mcp.tool.status").AsString(); got != "ok" || server.Status.Code != codes.Unset {
+		t.Errorf("status = %q, %v", got, server.Status)
+	}
+	if got := attr(server, "mcp.tool.arguments").AsString(); got != `{"password":"[REDACTED]","thingId":"t1"}` {
+		t.Errorf("mcp.tool.arguments = %s", got)
+	}
+
+	if client.Name != "GET" || client.SpanKind != trace.SpanKindClient || client.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("upstream span = %s (%v), parent %v", client.Name, client.SpanKind, client.Parent.SpanID())
+	}
+	if got := attr(client, "http.response.status_code").AsInt64(); got != 200 {
+		t.Errorf("http.response.status_code = %d", got)
+	}
+	if got := attr(client, "url.full").AsString(); strings.Contains(got, "s3cr3t") {
+		t.Errorf("url.full = %s", got)
+	}
+	if !strings.Contains(traceparent, client.SpanContext.TraceID().String()) || !strings.Contains(traceparent, client.SpanContext.SpanID().String()) {
+		t.Errorf("traceparent = %q, want the upstream span", traceparent)
+	}
+}
+
+func TestInstrumentFailures(t *testing.T) {
+	exporter := record(t)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	// An upstream error status fails both spans
+	call(t, fetchTool(srv, "/missing"), nil)
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	client, server := spans[0], spans[1]
+	if got := attr(client, "http.response.status_code").AsInt64(); got != 404 || client.Status.Code != codes.Error || client.Status.Description != "404 Not Found" {
+		t.Errorf("upstream span: status code %d, %v", got, client.Status)
+	}
+	if server.Status.Code != codes.Error || server.Status.Description != "Appwrite returned 404 Not Found" || attr(server, "mcp.tool.status").AsString() != "error" {
+		t.Errorf("tool span: %v, %s", server.Status, attr(server, "mcp.tool.status").AsString())
+	}
+
+	// A handler error is recorded without the secrets it echoes
+	exporter.Reset()
+	failing := models.Tool{
+		Definition: mcp.NewTool("post_thing"),
+		Handler: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("rejected password hunter22")
+		},
+	}
+	call(t, failing, map[string]any{"password": "hunter22"})
+	spans = exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	server = spans[0]
+	if server.Status.Code != codes.Error || server.Status.Description != "rejected password [REDACTED]" || attr(server, "mcp.tool.status").AsString() != "error" {
+		t.Errorf("tool span: %v", server.Status)
+	}
+	if len(server.Events) != 1 || server.Events[0].Name != "exception" || strings.Contains(attr(tracetest.SpanStub{Attributes: server.Events[0].Attributes}, "exception.message").AsString(), "hunter22") {
+		t.Errorf("events = %+v", server.Events)
+	}
+
+	// So is an upstream that cannot be reached
+	exporter.Reset()
+	srv.Close()
+	call(t, fetchTool(srv, "/thing"), nil)
+	spans = exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Status.Code != codes.Error || spans[1].Status.Code != codes.Error {
+		t.Errorf("unreachable upstream: %+v", spans)
+	}
+}
@@ -0,0 +1,183 @@
+// Package vault loads the server-side credential vault: a file mapping the
+// opaque tokens clients present to named Appwrite profiles, so project keys
+// never leave the server. The file may be stored encrypted with a key taken
+// from the environment.
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/appwrite/mcp-server/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Header is the first line of an encrypted vault file. The rest of the file
+// is the base64 of the GCM nonce followed by the sealed plaintext.
+const Header = "# appwrite-mcp-vault aes-256-gcm v1"
+
+// Vault maps client tokens to profiles.
+type Vault struct {
+	Clients  []Client                   `yaml:"clients" json:"clients"`
+	Profiles map[string]*config.Profile `yaml:"profiles" json:"profiles"`
+}
+
+// Client is one client allowed to use the server.
+type Client struct {
+	Label   string `yaml:"label" json:"label"`     // Name the client appears under in logs
+	Token   string `yaml:"token" json:"token"`     // Opaque token the client sends
+	Profile string `yaml:"profile" json:"profile"` // Profile the client's requests use
+}
+
+// Load reads the vault at path. Encrypted files are decrypted with key, the
+// base64 of a 32-byte AES key; plain files are read as they are. The file is
+// YAML or JSON, and unknown fields are errors.
+func Load(path, key string) (*Vault, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if Encrypted(data) {
+		if key == "" {
+			return nil, fmt.Errorf("%s is encrypted but VAULT_KEY is not set", path)
+		}
+		if data, err = Decrypt(data, key); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	v, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return v, nil
+}
+
+// Parse parses and validates a plaintext vault.
+func Parse(data []byte) (*Vault, error) {
+	var v Vault
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&v); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err := v.validate(); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// validate reports the first invalid field, named by its path in the file.
+func (v *Vault) validate() error {
+	if len(v.Clients) == 0 {
+		return errors.New("clients: no clients")
+	}
+	names := make([]string, 0, len(v.Profiles))
+	for name := range v.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := v.Profiles[name]
+		if p == nil {
+			return fmt.Errorf("profiles.%s: empty profile", name)
+		}
+		if err := p.Validate("profiles." + name); err != nil {
+			return err
+		}
+	}
+	labels := map[string]bool{}
+	tokens := map[string]bool{}
+	for i, c := range v.Clients {
+		field := fmt.Sprintf("clients[%d]", i)
+		switch {
+		case c.Label == "":
+			return fmt.Errorf("%s.label: required", field)
+		case labels[c.Label]:
+			return fmt.Errorf("%s.label: duplicate label %q", field, c.Label)
+		case c.Token == "":
+			return fmt.Errorf("%s.token: required", field)
+		case tokens[c.Token]:
+			return fmt.Errorf("%s.token: duplicate token", field)
+		case c.Profile == "":
+			return fmt.Errorf("%s.profile: required", field)
+		case v.Profiles[c.Profile] == nil:
+			return fmt.Errorf("%s.profile: unknown profile %q", field, c.Profile)
+		}
+		labels[c.Label] = true
+		tokens[c.Token] = true
+	}
+	return nil
+}
+
+// Encrypted reports whether data is an encrypted vault file.
+func Encrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(Header+"\n"))
+}
+
+// Encrypt seals a plaintext vault with key.
+func Encrypt(plaintext []byte, key string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, []byte(Header))
+	return []byte(Header + "\n" + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Decrypt opens an encrypted vault file with key.
+func Decrypt(data []byte, key string) ([]byte, error) {
+	if !Encrypted(data) {
+		return nil, errors.New("not an encrypted vault")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(string(data[len(Header)+1:]))
+	sealed, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("decode vault: %v", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("decode vault: too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(Header))
+	if err != nil {
+		return nil, errors.New("decrypt vault: wrong key or corrupted file")
+	}
+	return plaintext, nil
+}
+
+// NewKey returns a random key in the form Encrypt and Decrypt expect.
+func NewKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func newGCM(key string) (cipher.AEAD, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("VAULT_KEY must be the base64 of 32 bytes")
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,74 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const plain = `clients:
+  - label: ci
+    token: ci-token
+    profile: prod
+profiles:
+  prod:
+    baseURL: https://cloud.appwrite.io/v1
+    project: prod-project
+    key: prod-key
+`
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	key, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := Encrypt([]byte(plain), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Encrypted(sealed) || strings.Contains(string(sealed), "prod-key") {
+		t.Fatalf("not sealed:\n%s", sealed)
+	}
+	path := filepath.Join(t.TempDir(), "vault")
+	os.WriteFile(path, sealed, 0o600)
+
+	v, err := Load(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Clients) != 1 || v.Clients[0].Token != "ci-token" || v.Profiles["prod"].Key != "prod-key" {
+		t.Errorf("vault = %+v", v)
+	}
+
+	other, _ := NewKey()
+	if _, err := Load(path, other); err == nil || !strings.Contains(err.Error(), "wrong key or corrupted file") {
+		t.Errorf("wrong key: err = %v", err)
+	}
+	if _, err := Load(path, ""); err == nil || !strings.Contains(err.Error(), "VAULT_KEY is not set") {
+		t.Errorf("no key: err = %v", err)
+	}
+	if _, err := Decrypt(sealed, "short"); err == nil || !strings.Contains(err.Error(), "32 bytes") {
+		t.Errorf("malformed key: err = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		clients, want string
+	}{
+		{"[]", "clients: no clients"},
+		{`[{label: a, token: t1, profile: prod}, {label: b, token: t2, profile: staging}]`, `clients[1].profile: unknown profile "staging"`},
+		{`[{label: a, token: t1, profile: prod}, {label: b, token: t1, profile: prod}]`, "clients[1].token: duplicate token"},
+		{`[{label: a, token: t1, profile: prod}, {label: a, token: t2, profile: prod}]`, `clients[1].label: duplicate label "a"`},
+		{`[{label: a, profile: prod}]`, "clients[0].token: required"},
+	} {
+		data := "clients: " + tc.clients + "\nprofiles:\n  prod:\n    baseURL: https://cloud.appwrite.io/v1\n    project: p\n"
+		if _, err := Parse([]byte(data)); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: err = %v, want %s", tc.clients, err, tc.want)
+		}
+	}
+	if _, err := Parse([]byte(plain + "extra: true\n")); err == nil {
+		t.Error("unknown field accepted")
+	}
+}